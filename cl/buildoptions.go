@@ -0,0 +1,155 @@
+package cl
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// BuildOptions describes the options passed to the OpenCL compiler when
+// building a program. Use String to render them for BuildProgram or use
+// Program.BuildProgramWithOptions to validate and build in one step.
+type BuildOptions struct {
+	// Defines are preprocessor macros passed as -D name=value. An empty
+	// value defines the macro as -D name (i.e. with the value 1).
+	Defines map[string]string
+	// IncludeDirs are directories added to the header search path (-I dir).
+	IncludeDirs []string
+	// Std selects the OpenCL C language version (e.g. "CL1.2" or "CL2.0")
+	// passed as -cl-std.
+	Std string
+
+	// Math intrinsics options
+	SinglePrecisionConstant        bool // -cl-single-precision-constant
+	DenormsAreZero                 bool // -cl-denorms-are-zero
+	FP32CorrectlyRoundedDivideSqrt bool // -cl-fp32-correctly-rounded-divide-sqrt
+
+	// Optimization options
+	OptDisable              bool // -cl-opt-disable
+	MadEnable               bool // -cl-mad-enable
+	NoSignedZeros           bool // -cl-no-signed-zeros
+	UnsafeMathOptimizations bool // -cl-unsafe-math-optimizations
+	FiniteMathOnly          bool // -cl-finite-math-only
+	FastRelaxedMath         bool // -cl-fast-relaxed-math
+	UniformWorkGroupSize    bool // -cl-uniform-work-group-size
+
+	DisableWarnings  bool // -w
+	WarningsAsErrors bool // -Werror
+	KernelArgInfo    bool // -cl-kernel-arg-info
+	Debug            bool // -g
+
+	// Extra options are appended verbatim (e.g. vendor specific flags).
+	Extra []string
+}
+
+// String renders the options in the form expected by clBuildProgram.
+// Defines are sorted by name so the output is deterministic.
+func (o *BuildOptions) String() string {
+	var opts []string
+	names := make([]string, 0, len(o.Defines))
+	for name := range o.Defines {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if value := o.Defines[name]; value != "" {
+			opts = append(opts, "-D", quoteBuildOption(name+"="+value))
+		} else {
+			opts = append(opts, "-D", quoteBuildOption(name))
+		}
+	}
+	for _, dir := range o.IncludeDirs {
+		opts = append(opts, "-I", quoteBuildOption(dir))
+	}
+	if o.Std != "" {
+		opts = append(opts, "-cl-std="+o.Std)
+	}
+	flags := []struct {
+		set  bool
+		flag string
+	}{
+		{o.SinglePrecisionConstant, "-cl-single-precision-constant"},
+		{o.DenormsAreZero, "-cl-denorms-are-zero"},
+		{o.FP32CorrectlyRoundedDivideSqrt, "-cl-fp32-correctly-rounded-divide-sqrt"},
+		{o.OptDisable, "-cl-opt-disable"},
+		{o.MadEnable, "-cl-mad-enable"},
+		{o.NoSignedZeros, "-cl-no-signed-zeros"},
+		{o.UnsafeMathOptimizations, "-cl-unsafe-math-optimizations"},
+		{o.FiniteMathOnly, "-cl-finite-math-only"},
+		{o.FastRelaxedMath, "-cl-fast-relaxed-math"},
+		{o.UniformWorkGroupSize, "-cl-uniform-work-group-size"},
+		{o.DisableWarnings, "-w"},
+		{o.WarningsAsErrors, "-Werror"},
+		{o.KernelArgInfo, "-cl-kernel-arg-info"},
+		{o.Debug, "-g"},
+	}
+	for _, f := range flags {
+		if f.set {
+			opts = append(opts, f.flag)
+		}
+	}
+	opts = append(opts, o.Extra...)
+	return strings.Join(opts, " ")
+}
+
+// Validate checks that the macro names are valid identifiers and, if device
+// is not nil, that the device supports the requested OpenCL C version.
+func (o *BuildOptions) Validate(device *Device) error {
+	for name := range o.Defines {
+		if !isIdentifier(name) {
+			return fmt.Errorf("cl: invalid macro name %q in build options", name)
+		}
+	}
+	if o.Std == "" {
+		return nil
+	}
+	var major, minor int
+	if _, err := fmt.Sscanf(o.Std, "CL%d.%d", &major, &minor); err != nil || o.Std != fmt.Sprintf("CL%d.%d", major, minor) {
+		return fmt.Errorf("cl: invalid -cl-std value %q in build options", o.Std)
+	}
+	if device == nil {
+		return nil
+	}
+	// OpenCL 3.0 devices may report a 1.x OpenCL C version for
+	// compatibility, so CL3.0 is checked against the device version.
+	if major >= 3 {
+		if !device.versionAtLeast(major, minor) {
+			return fmt.Errorf("cl: -cl-std=%s not supported by device %s (%s)", o.Std, device.Name(), device.Version())
+		}
+		return nil
+	}
+	cVersion := device.OpenCLCVersion()
+	devMajor, devMinor, ok := parseVersion(cVersion)
+	if !ok {
+		return fmt.Errorf("cl: unable to parse OpenCL C version %q of device %s", cVersion, device.Name())
+	}
+	if devMajor < major || (devMajor == major && devMinor < minor) {
+		return fmt.Errorf("cl: -cl-std=%s not supported by device %s (%s)", o.Std, device.Name(), cVersion)
+	}
+	return nil
+}
+
+// quoteBuildOption quotes an option value if it contains whitespace or
+// characters that the compiler's option parser treats specially.
+func quoteBuildOption(s string) string {
+	if s != "" && !strings.ContainsAny(s, " \t\n\"'\\") {
+		return s
+	}
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+	return `"` + r.Replace(s) + `"`
+}
+
+func isIdentifier(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i, c := range s {
+		switch {
+		case c == '_', c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z':
+		case c >= '0' && c <= '9' && i > 0:
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,41 @@
+package cl
+
+import "testing"
+
+func TestBuildOptionsString(t *testing.T) {
+	cases := []struct {
+		opts     BuildOptions
+		expected string
+	}{
+		{BuildOptions{}, ""},
+		{
+			BuildOptions{
+				Defines:         map[string]string{"N": "16", "DEBUG": "", "MSG": `say "hi"`},
+				IncludeDirs:     []string{"inc", "/path with/space"},
+				Std:             "CL1.2",
+				FastRelaxedMath: true,
+				Debug:           true,
+				Extra:           []string{"-nv-verbose"},
+			},
+			`-D DEBUG -D "MSG=say \"hi\"" -D N=16 -I inc -I "/path with/space" -cl-std=CL1.2 -cl-fast-relaxed-math -g -nv-verbose`,
+		},
+		{BuildOptions{MadEnable: true, WarningsAsErrors: true}, "-cl-mad-enable -Werror"},
+	}
+	for _, c := range cases {
+		if s := c.opts.String(); s != c.expected {
+			t.Errorf("expected %q got %q", c.expected, s)
+		}
+	}
+}
+
+func TestBuildOptionsValidate(t *testing.T) {
+	if err := (&BuildOptions{Defines: map[string]string{"1BAD": ""}}).Validate(nil); err == nil {
+		t.Error("expected error for invalid macro name")
+	}
+	if err := (&BuildOptions{Std: "1.2"}).Validate(nil); err == nil {
+		t.Error("expected error for invalid -cl-std")
+	}
+	if err := (&BuildOptions{Std: "CL2.0", Defines: map[string]string{"N_2": "1"}}).Validate(nil); err != nil {
+		t.Errorf("unexpected error: %+v", err)
+	}
+}
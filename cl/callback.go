@@ -0,0 +1,69 @@
+package cl
+
+// #include <stdint.h>
+// #include "cl.h"
+import "C"
+
+import "sync"
+
+// Go values can't be passed through C, so callbacks given to the OpenCL
+// runtime are registered here and C gets an id to look them up with.
+var callbacks = struct {
+	sync.Mutex
+	next uintptr
+	m    map[uintptr]interface{}
+	// statusCallbacks holds the ids of the Submitted and Running callbacks
+	// of an event, which aren't called if its command terminates
+	// abnormally.
+	statusCallbacks map[C.cl_event][]uintptr
+}{m: make(map[uintptr]interface{}), statusCallbacks: make(map[C.cl_event][]uintptr)}
+
+func registerCallback(fn interface{}) uintptr {
+	callbacks.Lock()
+	defer callbacks.Unlock()
+	callbacks.next++
+	callbacks.m[callbacks.next] = fn
+	return callbacks.next
+}
+
+// unregisterCallback removes the callback with the id and returns it.
+func unregisterCallback(id uintptr) interface{} {
+	callbacks.Lock()
+	defer callbacks.Unlock()
+	fn := callbacks.m[id]
+	delete(callbacks.m, id)
+	return fn
+}
+
+// trackStatusCallback records id as a Submitted or Running callback of
+// event. It returns true for the first one of the event, when the caller
+// has to arrange for untrackStatusCallbacks to be called once the event
+// reaches a terminal status.
+func trackStatusCallback(event C.cl_event, id uintptr) bool {
+	callbacks.Lock()
+	defer callbacks.Unlock()
+	ids, ok := callbacks.statusCallbacks[event]
+	callbacks.statusCallbacks[event] = append(ids, id)
+	return !ok
+}
+
+// untrackStatusCallbacks forgets the Submitted and Running callbacks of
+// event. If the command terminated abnormally they are also unregistered
+// since they will never be called.
+func untrackStatusCallbacks(event C.cl_event, abnormal bool) {
+	callbacks.Lock()
+	defer callbacks.Unlock()
+	if abnormal {
+		for _, id := range callbacks.statusCallbacks[event] {
+			delete(callbacks.m, id)
+		}
+	}
+	delete(callbacks.statusCallbacks, event)
+}
+
+//export goEventCallback
+func goEventCallback(event C.cl_event, status C.cl_int, id C.uintptr_t) {
+	if fn, ok := unregisterCallback(uintptr(id)).(func(C.cl_event, C.cl_int)); ok {
+		fn(event, status)
+	}
+}
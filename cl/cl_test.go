@@ -1,8 +1,12 @@
 package cl
 
 import (
+	"fmt"
+	"io"
 	"math/rand"
 	"testing"
+	"time"
+	"unsafe"
 )
 
 var kernelSource = `
@@ -118,6 +122,30 @@ func TestHello(t *testing.T) {
 	if err != nil {
 		t.Fatalf("CreateCommandQueue failed: %+v", err)
 	}
+	hintedQueue, err := context.CreateCommandQueueWithProperties(device, QueueProperties{Properties: CommandQueueProfilingEnable, Priority: QueuePriorityLow, Throttle: QueueThrottleLow})
+	if err == ErrUnsupported {
+		t.Logf("Queue hints unsupported")
+		hintedQueue, err = context.CreateCommandQueueWithProperties(device, QueueProperties{Properties: CommandQueueProfilingEnable})
+	}
+	if err != nil {
+		t.Fatalf("CreateCommandQueueWithProperties failed: %+v", err)
+	}
+	if properties, err := hintedQueue.Properties(); err != nil {
+		t.Errorf("Properties failed: %+v", err)
+	} else if properties&CommandQueueProfilingEnable == 0 {
+		t.Errorf("Expected queue properties to include ProfilingEnable, got %s", properties)
+	}
+	if hintedQueue.Device().id != device.id {
+		t.Errorf("Queue device doesn't match the device it was created for")
+	}
+	if queueContext, err := hintedQueue.Context(); err != nil {
+		t.Errorf("Context failed: %+v", err)
+	} else if queueContext.clContext != context.clContext {
+		t.Errorf("Queue context doesn't match the context it was created in")
+	} else {
+		queueContext.Release()
+	}
+	hintedQueue.Release()
 	program, err := context.CreateProgramWithSource([]string{kernelSource})
 	if err != nil {
 		t.Fatalf("CreateProgramWithSource failed: %+v", err)
@@ -140,6 +168,15 @@ func TestHello(t *testing.T) {
 			t.Logf("Kernel arg %d: %s", i, name)
 		}
 	}
+	if args, err := kernel.Args(); err == ErrUnsupported || err == ErrKernelArgInfoNotAvailable {
+		t.Logf("Kernel arg info: %+v", err)
+	} else if err != nil {
+		t.Errorf("Args failed: %+v", err)
+	} else {
+		for i, arg := range args {
+			t.Logf("Kernel arg %d: %s %s (%s, %s)", i, arg.TypeName, arg.Name, arg.AddressQualifier, arg.TypeQualifier)
+		}
+	}
 	input, err := context.CreateEmptyBuffer(MemReadOnly, 4*len(data))
 	if err != nil {
 		t.Fatalf("CreateBuffer failed for input: %+v", err)
@@ -162,15 +199,57 @@ func TestHello(t *testing.T) {
 	t.Logf("Work group size: %d", local)
 	size, _ := kernel.PreferredWorkGroupSizeMultiple(nil)
 	t.Logf("Preferred Work Group Size Multiple: %d", size)
+	if name, err := kernel.FunctionName(); err != nil {
+		t.Errorf("FunctionName failed: %+v", err)
+	} else if name != "square" {
+		t.Errorf("Expected kernel function name square, got %s", name)
+	}
+	localMem, err := kernel.LocalMemSize(device)
+	if err != nil {
+		t.Fatalf("LocalMemSize failed: %+v", err)
+	}
+	t.Logf("Kernel Local Mem Size: %d", localMem)
+	clone, err := kernel.Clone()
+	if err != nil {
+		t.Fatalf("Clone failed: %+v", err)
+	}
+	if name, err := clone.FunctionName(); err != nil {
+		t.Errorf("FunctionName of clone failed: %+v", err)
+	} else if name != "square" {
+		t.Errorf("Expected cloned kernel function name square, got %s", name)
+	}
+	clone.Release()
 
 	global := len(data)
 	d := len(data) % local
 	if d != 0 {
 		global += local - d
 	}
-	if _, err := queue.EnqueueNDRangeKernel(kernel, nil, []int{global}, []int{local}, nil); err != nil {
+	event, err := queue.EnqueueNDRangeKernel(kernel, nil, []int{global}, []int{local}, nil)
+	if err != nil {
 		t.Fatalf("EnqueueNDRangeKernel failed: %+v", err)
 	}
+	if err := queue.Flush(); err != nil {
+		t.Fatalf("Flush failed: %+v", err)
+	}
+	select {
+	case <-event.Done():
+		if err := event.Err(); err != nil {
+			t.Fatalf("Kernel execution failed: %+v", err)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("Timeout waiting for kernel execution")
+	}
+	if commandType, err := event.CommandType(); err != nil {
+		t.Errorf("CommandType failed: %+v", err)
+	} else if commandType != CommandTypeNDRangeKernel {
+		t.Errorf("Expected command type NDRangeKernel, got %s", commandType)
+	}
+	if status, err := event.CommandExecutionStatus(); err != nil {
+		t.Errorf("CommandExecutionStatus failed: %+v", err)
+	} else if status != CommmandExecStatusComplete {
+		t.Errorf("Expected command status Complete, got %s", status)
+	}
 
 	if err := queue.Finish(); err != nil {
 		t.Fatalf("Finish failed: %+v", err)
@@ -191,4 +270,105 @@ func TestHello(t *testing.T) {
 	if correct != len(data) {
 		t.Fatalf("%d/%d correct values", correct, len(data))
 	}
+
+	fillValue := float32(2)
+	fill, err := queue.EnqueueFillBuffer(output, unsafe.Pointer(&fillValue), 4, 0, 4*len(data), nil)
+	if err != nil {
+		t.Fatalf("EnqueueFillBuffer failed: %+v", err)
+	}
+	marker, err := queue.EnqueueMarkerWithWaitList([]*Event{fill})
+	if err != nil {
+		t.Fatalf("EnqueueMarkerWithWaitList failed: %+v", err)
+	}
+	filled := make([]float32, len(data))
+	if _, err := queue.EnqueueReadBufferFloat32(output, true, 0, filled, []*Event{marker}); err != nil {
+		t.Fatalf("EnqueueReadBufferFloat32 failed: %+v", err)
+	}
+	for i, v := range filled {
+		if v != fillValue {
+			t.Fatalf("Filled value %d is %f, expected %f", i, v, fillValue)
+		}
+	}
+
+	graph := NewGraph(context)
+	graphResults := make([]float32, len(data))
+	write := graph.AddWrite(input, 0, data[:])
+	square := graph.AddKernel(kernel, NDRange{Global: []int{global}, Local: []int{local}}, []interface{}{input, output, uint32(len(data))}, write)
+	graph.AddRead(output, 0, graphResults, square)
+	done, err := graph.Execute(queue)
+	if err != nil {
+		t.Fatalf("Graph.Execute failed: %+v", err)
+	}
+	if err := done.Wait(); err != nil {
+		t.Fatalf("Waiting for graph failed: %+v", err)
+	}
+	for i, v := range data {
+		if graphResults[i] != v*v {
+			t.Fatalf("Graph result %d is %f, expected %f", i, graphResults[i], v*v)
+		}
+	}
+
+	const chunkLen = 256
+	remaining := data[:]
+	pipeline := &Pipeline{
+		Kernel:    kernel,
+		Depth:     2,
+		ChunkSize: chunkLen * 4,
+		Source: func(chunk []byte) (int, error) {
+			n := copy(chunk, hostBytes(unsafe.Pointer(&remaining[0]), len(remaining)*4))
+			remaining = remaining[n/4:]
+			if len(remaining) == 0 {
+				return n, io.EOF
+			}
+			return n, nil
+		},
+		Setup: func(k *Kernel, in, out *MemObject, n int) (NDRange, int, error) {
+			r, _ := NDRange{Global: []int{n / 4}, Local: []int{local}}.RoundUp()
+			return r, n, k.SetArgs(in, out, uint32(n/4))
+		},
+		Sink: func(index int, out []byte) error {
+			for i := 0; i < len(out)/4; i++ {
+				v := data[index*chunkLen+i]
+				if got := *(*float32)(unsafe.Pointer(&out[i*4])); got != v*v {
+					return fmt.Errorf("pipeline result %d of chunk %d is %f, expected %f", i, index, got, v*v)
+				}
+			}
+			return nil
+		},
+	}
+	stats, err := pipeline.Run(context, device)
+	if err != nil {
+		t.Fatalf("Pipeline.Run failed: %+v", err)
+	}
+	if stats.Chunks != len(data)/chunkLen || stats.BytesOut != int64(len(data)*4) {
+		t.Fatalf("Unexpected pipeline stats: %s", stats)
+	}
+	t.Logf("Pipeline: %s", stats)
+
+	if device.ExecutionCapabilities()&ExecCapabilityNativeKernel != 0 {
+		var sum float32
+		native := func(args []byte) {
+			offset := NativeKernelArgsOffset(1)
+			if len(args) != offset+1 || args[offset] != 42 {
+				t.Errorf("Expected native kernel args [42], got %v", args[offset:])
+			}
+			ptr := NativeKernelPointer(args, 0)
+			for _, v := range (*[1 << 26]float32)(ptr)[:len(data):len(data)] {
+				sum += v
+			}
+		}
+		if _, err := queue.EnqueueNativeKernel(native, []byte{42}, []*MemObject{output}, nil); err != nil {
+			t.Fatalf("EnqueueNativeKernel failed: %+v", err)
+		}
+		if err := queue.Finish(); err != nil {
+			t.Fatalf("Finish failed: %+v", err)
+		}
+		var expected float32
+		for _, v := range results {
+			expected += v
+		}
+		if sum != expected {
+			t.Errorf("Expected native kernel sum %f, got %f", expected, sum)
+		}
+	}
 }
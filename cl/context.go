@@ -6,6 +6,8 @@ import "C"
 
 import (
 	"runtime"
+	"strings"
+	"sync"
 	"unsafe"
 )
 
@@ -14,6 +16,11 @@ const maxImageFormats = 256
 type Context struct {
 	clContext C.cl_context
 	devices   []*Device
+
+	// fillKernel emulates EnqueueFillBuffer in the cl10 build. It's
+	// created on first use and released with the context.
+	fillMu     sync.Mutex
+	fillKernel *Kernel
 }
 
 type MemObject struct {
@@ -22,6 +29,12 @@ type MemObject struct {
 }
 
 func releaseContext(c *Context) {
+	if c.fillKernel != nil {
+		program := c.fillKernel.program
+		c.fillKernel.Release()
+		program.Release()
+		c.fillKernel = nil
+	}
 	if c.clContext != nil {
 		C.clReleaseContext(c.clContext)
 		c.clContext = nil
@@ -45,6 +58,35 @@ func (b *MemObject) Release() {
 	releaseMemObject(b)
 }
 
+// retainContext returns a Context for an existing cl_context (e.g. as
+// returned by an info query), retaining it for the lifetime of the Context.
+func retainContext(clContext C.cl_context) (*Context, error) {
+	if clContext == nil {
+		return nil, ErrUnknown
+	}
+	var numDevices C.cl_uint
+	if err := C.clGetContextInfo(clContext, C.CL_CONTEXT_NUM_DEVICES, C.size_t(unsafe.Sizeof(numDevices)), unsafe.Pointer(&numDevices), nil); err != C.CL_SUCCESS {
+		return nil, toError(err)
+	}
+	var devices []*Device
+	if numDevices > 0 {
+		deviceIds := make([]C.cl_device_id, numDevices)
+		if err := C.clGetContextInfo(clContext, C.CL_CONTEXT_DEVICES, C.size_t(unsafe.Sizeof(deviceIds[0]))*C.size_t(numDevices), unsafe.Pointer(&deviceIds[0]), nil); err != C.CL_SUCCESS {
+			return nil, toError(err)
+		}
+		devices = make([]*Device, numDevices)
+		for i, id := range deviceIds {
+			devices[i] = &Device{id: id}
+		}
+	}
+	if err := C.clRetainContext(clContext); err != C.CL_SUCCESS {
+		return nil, toError(err)
+	}
+	context := &Context{clContext: clContext, devices: devices}
+	runtime.SetFinalizer(context, releaseContext)
+	return context, nil
+}
+
 // TODO: properties
 func CreateContext(devices []*Device) (*Context, error) {
 	deviceIds := buildDeviceIdList(devices)
@@ -86,7 +128,45 @@ func (ctx *Context) CreateCommandQueue(device *Device, properties CommandQueuePr
 	if clQueue == nil {
 		return nil, ErrUnknown
 	}
-	commandQueue := &CommandQueue{clQueue: clQueue, device: device}
+	commandQueue := &CommandQueue{clQueue: clQueue, device: device, context: ctx}
+	runtime.SetFinalizer(commandQueue, releaseCommandQueue)
+	return commandQueue, nil
+}
+
+// CreateCommandQueueWithProperties creates a command queue with
+// clCreateCommandQueueWithProperties when the package is built with the
+// cl21 or cl22 tag and the device supports OpenCL 2.0. Otherwise it uses
+// clCreateCommandQueueWithPropertiesKHR if the device has the
+// cl_khr_create_command_queue extension (which the priority and throttle
+// hint extensions require on OpenCL 1.2), and falls back to
+// CreateCommandQueue when no hints are given. It returns ErrUnsupported for
+// hints the device doesn't have the extension for, and for on-device
+// queues and a queue size without OpenCL 2.0.
+func (ctx *Context) CreateCommandQueueWithProperties(device *Device, properties QueueProperties) (*CommandQueue, error) {
+	extensions := " " + device.Extensions() + " "
+	if properties.Priority != QueuePriorityDefault && !strings.Contains(extensions, " cl_khr_priority_hints ") {
+		return nil, ErrUnsupported
+	}
+	if properties.Throttle != QueueThrottleDefault && !strings.Contains(extensions, " cl_khr_throttle_hints ") {
+		return nil, ErrUnsupported
+	}
+	create := createCommandQueueWithProperties
+	if create == nil || !device.versionAtLeast(2, 0) {
+		if properties.Properties&(CommandQueueOnDevice|CommandQueueOnDeviceDefault) != 0 || properties.Size != 0 {
+			return nil, ErrUnsupported
+		}
+		if properties.Priority == QueuePriorityDefault && properties.Throttle == QueueThrottleDefault {
+			return ctx.CreateCommandQueue(device, properties.Properties)
+		}
+		if create = createCommandQueueWithPropertiesKHR; create == nil {
+			return nil, ErrUnsupported
+		}
+	}
+	clQueue, err := create(ctx, device, properties.list())
+	if err != nil {
+		return nil, err
+	}
+	commandQueue := &CommandQueue{clQueue: clQueue, device: device, context: ctx}
 	runtime.SetFinalizer(commandQueue, releaseCommandQueue)
 	return commandQueue, nil
 }
@@ -111,6 +191,35 @@ func (ctx *Context) CreateProgramWithSource(sources []string) (*Program, error)
 	return program, nil
 }
 
+// CreateProgramWithIL creates a program object for the context and loads
+// the intermediate language (e.g. SPIR-V) in il into it. When built with
+// the cl21 or cl22 tag and every device in the context supports OpenCL 2.1,
+// clCreateProgramWithIL is used. Otherwise it falls back to the
+// cl_khr_il_program extension, returning ErrUnsupported if that is not
+// available either.
+func (ctx *Context) CreateProgramWithIL(il []byte) (*Program, error) {
+	if len(il) == 0 {
+		return nil, ErrInvalidValue
+	}
+	create := createProgramWithILKHR
+	if createProgramWithIL != nil && ctx.versionAtLeast(2, 1) {
+		create = createProgramWithIL
+	}
+	if create == nil {
+		return nil, ErrUnsupported
+	}
+	clProgram, err := create(ctx, il)
+	if err != nil {
+		return nil, err
+	}
+	if clProgram == nil {
+		return nil, ErrUnknown
+	}
+	program := &Program{clProgram: clProgram, devices: ctx.devices}
+	runtime.SetFinalizer(program, releaseProgram)
+	return program, nil
+}
+
 func (ctx *Context) CreateBufferUnsafe(flags MemFlag, size int, dataPtr unsafe.Pointer) (*MemObject, error) {
 	var err C.cl_int
 	clBuffer := C.clCreateBuffer(ctx.clContext, C.cl_mem_flags(flags), C.size_t(size), dataPtr, &err)
@@ -135,6 +244,17 @@ func (ctx *Context) CreateBufferFloat32(flags MemFlag, data []float32) (*MemObje
 	return ctx.CreateBufferUnsafe(flags, len(data)*4, unsafe.Pointer(&data[0]))
 }
 
+// CreateBufferSlice creates a buffer initialized from a slice of numbers,
+// vectors such as Float4, or arrays of them. Slices of structs and bools
+// are rejected since their Go memory layout doesn't match OpenCL C.
+func (ctx *Context) CreateBufferSlice(flags MemFlag, slice interface{}) (*MemObject, error) {
+	dataPtr, size, err := slicePointer(slice)
+	if err != nil {
+		return nil, err
+	}
+	return ctx.CreateBufferUnsafe(flags, size, dataPtr)
+}
+
 func (ctx *Context) CreateUserEvent() (*Event, error) {
 	var err C.cl_int
 	clEvent := C.clCreateUserEvent(ctx.clContext, &err)
@@ -148,5 +268,16 @@ func (ctx *Context) Release() {
 	releaseContext(ctx)
 }
 
+// versionAtLeast reports whether all devices in the context support
+// OpenCL major.minor or later.
+func (ctx *Context) versionAtLeast(major, minor int) bool {
+	for _, d := range ctx.devices {
+		if !d.versionAtLeast(major, minor) {
+			return false
+		}
+	}
+	return len(ctx.devices) > 0
+}
+
 // http://www.khronos.org/registry/cl/sdk/1.2/docs/man/xhtml/clCreateSubBuffer.html
 // func (memObject *MemObject) CreateSubBuffer(flags MemFlag, bufferCreateType BufferCreateType, )
@@ -0,0 +1,36 @@
+// +build !cl10
+
+package cl
+
+// #include <stdlib.h>
+// #include "cl.h"
+import "C"
+
+import (
+	"runtime"
+	"strings"
+	"unsafe"
+)
+
+// CreateProgramWithBuiltInKernels creates a program object for the context
+// and loads the built-in kernels named in names into it. The kernels must be
+// supported by all of the devices as reported by Device.BuiltInKernels.
+func (ctx *Context) CreateProgramWithBuiltInKernels(devices []*Device, names []string) (*Program, error) {
+	if len(devices) == 0 || len(names) == 0 {
+		return nil, ErrInvalidValue
+	}
+	deviceIds := buildDeviceIdList(devices)
+	cNames := C.CString(strings.Join(names, ";"))
+	defer C.free(unsafe.Pointer(cNames))
+	var err C.cl_int
+	clProgram := C.clCreateProgramWithBuiltInKernels(ctx.clContext, C.cl_uint(len(devices)), &deviceIds[0], cNames, &err)
+	if err != C.CL_SUCCESS {
+		return nil, toError(err)
+	}
+	if clProgram == nil {
+		return nil, ErrUnknown
+	}
+	program := &Program{clProgram: clProgram, devices: devices}
+	runtime.SetFinalizer(program, releaseProgram)
+	return program, nil
+}
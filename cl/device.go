@@ -4,6 +4,7 @@ package cl
 import "C"
 
 import (
+	"fmt"
 	"strings"
 	"unsafe"
 )
@@ -208,6 +209,34 @@ func (d *Device) DriverVersion() string {
 	return str
 }
 
+// Platform returns the platform associated with the device.
+func (d *Device) Platform() *Platform {
+	var platformId C.cl_platform_id
+	if err := C.clGetDeviceInfo(d.id, C.CL_DEVICE_PLATFORM, C.size_t(unsafe.Sizeof(platformId)), unsafe.Pointer(&platformId), nil); err != C.CL_SUCCESS {
+		panic("Failed to get device platform")
+	}
+	return &Platform{id: platformId}
+}
+
+// versionAtLeast reports whether the OpenCL version supported by the
+// device is major.minor or later.
+func (d *Device) versionAtLeast(major, minor int) bool {
+	maj, min, ok := parseVersion(d.Version())
+	return ok && (maj > major || (maj == major && min >= minor))
+}
+
+// parseVersion extracts the major and minor version from strings of the
+// form "OpenCL <major>.<minor> <vendor-specific information>" or
+// "OpenCL C <major>.<minor> <vendor-specific information>".
+func parseVersion(version string) (major, minor int, ok bool) {
+	for _, field := range strings.Fields(version) {
+		if _, err := fmt.Sscanf(field, "%d.%d", &major, &minor); err == nil {
+			return major, minor, true
+		}
+	}
+	return 0, 0, false
+}
+
 // The default compute device address space size specified as an
 // unsigned integer value in bits. Currently supported values are 32 or 64 bits.
 func (d *Device) AddressBits() int {
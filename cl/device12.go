@@ -3,8 +3,15 @@
 package cl
 
 // #include "cl.h"
+//
+// #ifndef CL_DEVICE_IL_VERSION_KHR
+// #define CL_DEVICE_IL_VERSION_KHR 0x105B
+// #endif
 import "C"
-import "unsafe"
+import (
+	"strings"
+	"unsafe"
+)
 
 const FPConfigCorrectlyRoundedDivideSqrt FPConfig = C.CL_FP_CORRECTLY_ROUNDED_DIVIDE_SQRT
 
@@ -12,9 +19,17 @@ func init() {
 	fpConfigNameMap[FPConfigCorrectlyRoundedDivideSqrt] = "CorrectlyRoundedDivideSqrt"
 }
 
-func (d *Device) BuiltInKernels() string {
+// BuiltInKernels returns the names of the built-in kernels supported by the
+// device. They can be used with Context.CreateProgramWithBuiltInKernels.
+func (d *Device) BuiltInKernels() []string {
 	str, _ := d.getInfoString(C.CL_DEVICE_BUILT_IN_KERNELS, true)
-	return str
+	var names []string
+	for _, name := range strings.Split(str, ";") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
 }
 
 // Is CL_FALSE if the implementation does not have a linker available. Is CL_TRUE if the linker is available. This can be CL_FALSE for the embedded platform profile only. This must be CL_TRUE if CL_DEVICE_COMPILER_AVAILABLE is CL_TRUE
@@ -45,3 +60,14 @@ func (d *Device) ImageMaxArraySize() int {
 	val, _ := d.getInfoSize(C.CL_DEVICE_IMAGE_MAX_ARRAY_SIZE, true)
 	return int(val)
 }
+
+// ILVersions returns the intermediate languages (e.g. "SPIR-V_1.0") that
+// can be loaded with CreateProgramWithIL. It returns nil if the device
+// supports neither OpenCL 2.1 nor the cl_khr_il_program extension.
+func (d *Device) ILVersions() []string {
+	str, err := d.getInfoString(C.CL_DEVICE_IL_VERSION_KHR, false)
+	if err != nil {
+		return nil
+	}
+	return strings.Fields(str)
+}
@@ -0,0 +1,188 @@
+package cl
+
+// #include <stdint.h>
+// #include "cl.h"
+//
+// extern void goEventCallback(cl_event event, cl_int status, uintptr_t id);
+//
+// static void CL_CALLBACK eventCallback(cl_event event, cl_int status, void *userData) {
+// 	goEventCallback(event, status, (uintptr_t)userData);
+// }
+//
+// static cl_int setEventCallback(cl_event event, cl_int status, uintptr_t id) {
+// 	return clSetEventCallback(event, status, eventCallback, (void *)id);
+// }
+import "C"
+
+import (
+	"time"
+	"unsafe"
+)
+
+// EventProfile holds the profiling info of a command. The timestamps are
+// the device time counter in nanoseconds.
+type EventProfile struct {
+	Queued int64 // when the command was enqueued by the host
+	Submit int64 // when the command was submitted to the device
+	Start  int64 // when the command started executing
+	End    int64 // when the command finished executing
+
+	QueueTime  time.Duration // Submit - Queued
+	SubmitTime time.Duration // Start - Submit
+	ExecTime   time.Duration // End - Start
+	TotalTime  time.Duration // End - Queued
+}
+
+func (e *Event) getInfoPointer(param C.cl_event_info, value unsafe.Pointer, size int) error {
+	return toError(C.clGetEventInfo(e.clEvent, param, C.size_t(size), value, nil))
+}
+
+// CommandType returns the type of the command the event belongs to.
+func (e *Event) CommandType() (CommandType, error) {
+	var commandType C.cl_command_type
+	err := e.getInfoPointer(C.CL_EVENT_COMMAND_TYPE, unsafe.Pointer(&commandType), int(unsafe.Sizeof(commandType)))
+	return CommandType(commandType), err
+}
+
+// CommandExecutionStatus returns the execution status of the command. A
+// negative status is the error code of a command that terminated
+// abnormally.
+func (e *Event) CommandExecutionStatus() (CommmandExecStatus, error) {
+	var status C.cl_int
+	err := e.getInfoPointer(C.CL_EVENT_COMMAND_EXECUTION_STATUS, unsafe.Pointer(&status), int(unsafe.Sizeof(status)))
+	return CommmandExecStatus(status), err
+}
+
+// CommandQueue returns the queue of the command or nil for user events.
+func (e *Event) CommandQueue() (*CommandQueue, error) {
+	var clQueue C.cl_command_queue
+	if err := e.getInfoPointer(C.CL_EVENT_COMMAND_QUEUE, unsafe.Pointer(&clQueue), int(unsafe.Sizeof(clQueue))); err != nil {
+		return nil, err
+	}
+	if clQueue == nil {
+		return nil, nil
+	}
+	return retainCommandQueue(clQueue)
+}
+
+// Context returns the context of the event.
+func (e *Event) Context() (*Context, error) {
+	var clContext C.cl_context
+	if err := e.getInfoPointer(C.CL_EVENT_CONTEXT, unsafe.Pointer(&clContext), int(unsafe.Sizeof(clContext))); err != nil {
+		return nil, err
+	}
+	return retainContext(clContext)
+}
+
+// ReferenceCount returns the reference count of the event. The value
+// should be considered immediately stale and is mainly useful for
+// identifying leaks.
+func (e *Event) ReferenceCount() (int, error) {
+	var count C.cl_uint
+	err := e.getInfoPointer(C.CL_EVENT_REFERENCE_COUNT, unsafe.Pointer(&count), int(unsafe.Sizeof(count)))
+	return int(count), err
+}
+
+// Wait blocks until the command of the event has completed.
+func (e *Event) Wait() error {
+	return WaitForEvents([]*Event{e})
+}
+
+// Profile returns the profiling info of the command. The queue must have
+// been created with CommandQueueProfilingEnable and the command must have
+// completed, otherwise ErrProfilingInfoNotAvailable is returned.
+func (e *Event) Profile() (EventProfile, error) {
+	var p EventProfile
+	for _, info := range []struct {
+		param ProfilingInfo
+		value *int64
+	}{
+		{ProfilingInfoCommandQueued, &p.Queued},
+		{ProfilingInfoCommandSubmit, &p.Submit},
+		{ProfilingInfoCommandStart, &p.Start},
+		{ProfilingInfoCommandEnd, &p.End},
+	} {
+		value, err := e.GetEventProfilingInfo(info.param)
+		if err != nil {
+			return EventProfile{}, err
+		}
+		*info.value = value
+	}
+	p.QueueTime = time.Duration(p.Submit - p.Queued)
+	p.SubmitTime = time.Duration(p.Start - p.Submit)
+	p.ExecTime = time.Duration(p.End - p.Start)
+	p.TotalTime = time.Duration(p.End - p.Queued)
+	return p, nil
+}
+
+// OnStatus registers fn to be called when the execution status of the
+// event changes to status (CommmandExecStatusSubmitted, Running or
+// Complete). A Complete callback is also called if the command terminates
+// abnormally, in which case err is the error for the negative status, and
+// Submitted and Running callbacks that haven't been called yet are
+// dropped. fn gets a new reference to the event, so it works even if e
+// has been released in the meantime. Callbacks are called from a thread
+// of the OpenCL implementation and should return quickly; they must not
+// call blocking OpenCL functions such as WaitForEvents or Finish.
+func (e *Event) OnStatus(status CommmandExecStatus, fn func(e *Event, err error)) error {
+	id := registerCallback(func(event C.cl_event, clStatus C.cl_int) {
+		var err error
+		if clStatus < 0 {
+			err = toError(clStatus)
+		}
+		fn(retainEvent(event), err)
+	})
+	if status != CommmandExecStatusComplete && trackStatusCallback(e.clEvent, id) {
+		sweep := registerCallback(func(event C.cl_event, clStatus C.cl_int) {
+			untrackStatusCallbacks(event, clStatus < 0)
+		})
+		if err := C.setEventCallback(e.clEvent, C.CL_COMPLETE, C.uintptr_t(sweep)); err != C.CL_SUCCESS {
+			unregisterCallback(sweep)
+			untrackStatusCallbacks(e.clEvent, true)
+			return toError(err)
+		}
+	}
+	if err := C.setEventCallback(e.clEvent, C.cl_int(status), C.uintptr_t(id)); err != C.CL_SUCCESS {
+		unregisterCallback(id)
+		return toError(err)
+	}
+	return nil
+}
+
+// retainEvent returns an Event for an existing cl_event (e.g. as passed to
+// a callback), retaining it for the lifetime of the Event.
+func retainEvent(clEvent C.cl_event) *Event {
+	C.clRetainEvent(clEvent)
+	return newEvent(clEvent)
+}
+
+// Done returns a channel that's closed when the command of the event has
+// completed or terminated, so that it can be waited on in a select (e.g.
+// together with a timeout or context cancellation). Unlike WaitForEvents
+// it doesn't block a thread while waiting.
+func (e *Event) Done() <-chan struct{} {
+	e.doneOnce.Do(func() {
+		e.done = make(chan struct{})
+		err := e.OnStatus(CommmandExecStatusComplete, func(_ *Event, err error) {
+			e.err = err
+			close(e.done)
+		})
+		if err != nil {
+			e.err = err
+			close(e.done)
+		}
+	})
+	return e.done
+}
+
+// Err returns nil if the command of the event hasn't completed yet or has
+// completed successfully. If the command terminated abnormally (or the
+// completion callback couldn't be registered) it returns the error.
+func (e *Event) Err() error {
+	select {
+	case <-e.Done():
+		return e.err
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,15 @@
+package cl
+
+import "testing"
+
+func TestEventStrings(t *testing.T) {
+	if s := CommandTypeNDRangeKernel.String(); s != "NDRangeKernel" {
+		t.Errorf("expected NDRangeKernel got %s", s)
+	}
+	if s := CommmandExecStatusRunning.String(); s != "Running" {
+		t.Errorf("expected Running got %s", s)
+	}
+	if s, expected := CommmandExecStatus(-5).String(), ErrOutOfResources.Error(); s != expected {
+		t.Errorf("expected %s got %s", expected, s)
+	}
+}
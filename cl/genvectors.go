@@ -0,0 +1,83 @@
+// +build ignore
+
+// This program generates vectors.go. Run it with go generate.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"io/ioutil"
+	"log"
+)
+
+var elemTypes = []struct {
+	name   string // Go vector type prefix
+	clName string // OpenCL C type
+	goType string
+	float  bool
+}{
+	{"Char", "char", "int8", false},
+	{"UChar", "uchar", "uint8", false},
+	{"Short", "short", "int16", false},
+	{"UShort", "ushort", "uint16", false},
+	{"Int", "int", "int32", false},
+	{"UInt", "uint", "uint32", false},
+	{"Long", "long", "int64", false},
+	{"ULong", "ulong", "uint64", false},
+	{"Float", "float", "float32", true},
+	{"Double", "double", "float64", true},
+}
+
+var widths = []int{2, 3, 4, 8, 16}
+
+func main() {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by genvectors.go; DO NOT EDIT.\n\n")
+	fmt.Fprintf(&buf, "package cl\n\nimport \"unsafe\"\n")
+	for _, et := range elemTypes {
+		for _, n := range widths {
+			writeVector(&buf, et.name, et.clName, et.goType, et.float, n)
+		}
+	}
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := ioutil.WriteFile("vectors.go", src, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func writeVector(buf *bytes.Buffer, name, clName, goType string, float bool, n int) {
+	typeName := fmt.Sprintf("%s%d", name, n)
+	storage := n
+	fmt.Fprintf(buf, "\n")
+	if n == 3 {
+		storage = 4
+		fmt.Fprintf(buf, "// %s is the OpenCL %s%d vector type. Like in OpenCL it has the size\n", typeName, clName, n)
+		fmt.Fprintf(buf, "// and alignment of a %s4 so the last component is padding.\n", clName)
+	} else {
+		fmt.Fprintf(buf, "// %s is the OpenCL %s%d vector type.\n", typeName, clName, n)
+	}
+	fmt.Fprintf(buf, "type %s [%d]%s\n\n", typeName, storage, goType)
+	fmt.Fprintf(buf, "func (v %s) Len() int { return %d }\n", typeName, n)
+	fmt.Fprintf(buf, "func (v %s) Size() int { return int(unsafe.Sizeof(v)) }\n", typeName)
+	fmt.Fprintf(buf, "func (v %s) unsafePointer() unsafe.Pointer { return unsafe.Pointer(&v) }\n", typeName)
+	if name == "Double" {
+		fmt.Fprintf(buf, "func (v %s) double() {}\n", typeName)
+	}
+	fmt.Fprintf(buf, "\n// Add returns the component-wise sum v+o.\n")
+	fmt.Fprintf(buf, "func (v %s) Add(o %s) %s {\n\tfor i := 0; i < %d; i++ {\n\t\tv[i] += o[i]\n\t}\n\treturn v\n}\n", typeName, typeName, typeName, n)
+	fmt.Fprintf(buf, "\n// Sub returns the component-wise difference v-o.\n")
+	fmt.Fprintf(buf, "func (v %s) Sub(o %s) %s {\n\tfor i := 0; i < %d; i++ {\n\t\tv[i] -= o[i]\n\t}\n\treturn v\n}\n", typeName, typeName, typeName, n)
+	fmt.Fprintf(buf, "\n// Mul returns the component-wise product v*o.\n")
+	fmt.Fprintf(buf, "func (v %s) Mul(o %s) %s {\n\tfor i := 0; i < %d; i++ {\n\t\tv[i] *= o[i]\n\t}\n\treturn v\n}\n", typeName, typeName, typeName, n)
+	fmt.Fprintf(buf, "\n// Scale returns v with every component multiplied by s.\n")
+	fmt.Fprintf(buf, "func (v %s) Scale(s %s) %s {\n\tfor i := 0; i < %d; i++ {\n\t\tv[i] *= s\n\t}\n\treturn v\n}\n", typeName, goType, typeName, n)
+	if float {
+		fmt.Fprintf(buf, "\n// Dot returns the dot product of v and o.\n")
+		fmt.Fprintf(buf, "func (v %s) Dot(o %s) %s {\n\tvar d %s\n\tfor i := 0; i < %d; i++ {\n\t\td += v[i] * o[i]\n\t}\n\treturn d\n}\n", typeName, typeName, goType, goType, n)
+	}
+}
@@ -0,0 +1,277 @@
+package cl
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrGraphHostCallback is reported by the event of a graph host node whose
+// callback failed. The error returned by the callback is available from
+// GraphNode.Err.
+var ErrGraphHostCallback = errors.New("cl: graph host callback failed")
+
+// graphHostCallbackStatus is the execution status of the user event of a
+// failed host callback. It's outside the ranges used by OpenCL and vendor
+// extensions.
+const graphHostCallbackStatus = -20000
+
+func init() {
+	errorMap[graphHostCallbackStatus] = ErrGraphHostCallback
+}
+
+type graphNodeKind int
+
+const (
+	graphKernel graphNodeKind = iota
+	graphCopy
+	graphWrite
+	graphRead
+	graphHost
+)
+
+// Graph is a set of commands with dependencies between them. Executing a
+// graph enqueues the commands on one or more command queues with the
+// event wait lists derived from the dependencies. A graph can be executed
+// repeatedly, e.g. after changing the arguments of its nodes.
+type Graph struct {
+	ctx   *Context
+	nodes []*GraphNode
+}
+
+// GraphNode is a command in a Graph.
+type GraphNode struct {
+	kind  graphNodeKind
+	deps  []*GraphNode
+	queue int // index of the queue to use or -1
+
+	kernel  *Kernel
+	ndRange NDRange
+	args    []interface{}
+
+	src, dst             *MemObject
+	srcOffset, dstOffset int
+	size                 int
+	data                 interface{}
+
+	host func() error
+
+	mu    sync.Mutex
+	event *Event
+	err   error
+}
+
+// NewGraph returns an empty graph for commands in ctx.
+func NewGraph(ctx *Context) *Graph {
+	return &Graph{ctx: ctx}
+}
+
+func (g *Graph) add(n *GraphNode, deps []*GraphNode) *GraphNode {
+	n.deps = deps
+	n.queue = -1
+	g.nodes = append(g.nodes, n)
+	return n
+}
+
+// AddKernel adds a node that sets args on kernel and enqueues it over r
+// after deps have completed.
+func (g *Graph) AddKernel(kernel *Kernel, r NDRange, args []interface{}, deps ...*GraphNode) *GraphNode {
+	return g.add(&GraphNode{kind: graphKernel, kernel: kernel, ndRange: r, args: args}, deps)
+}
+
+// AddCopy adds a node copying size bytes from src to dst.
+func (g *Graph) AddCopy(src, dst *MemObject, srcOffset, dstOffset, size int, deps ...*GraphNode) *GraphNode {
+	return g.add(&GraphNode{kind: graphCopy, src: src, dst: dst, srcOffset: srcOffset, dstOffset: dstOffset, size: size}, deps)
+}
+
+// AddWrite adds a node writing slice (see EnqueueWriteBufferSlice) to
+// buffer at offset. The slice must not be modified until the graph has
+// completed.
+func (g *Graph) AddWrite(buffer *MemObject, offset int, slice interface{}, deps ...*GraphNode) *GraphNode {
+	return g.add(&GraphNode{kind: graphWrite, dst: buffer, dstOffset: offset, data: slice}, deps)
+}
+
+// AddRead adds a node reading from buffer at offset into slice (see
+// EnqueueReadBufferSlice). The slice contains the data once the graph has
+// completed.
+func (g *Graph) AddRead(buffer *MemObject, offset int, slice interface{}, deps ...*GraphNode) *GraphNode {
+	return g.add(&GraphNode{kind: graphRead, src: buffer, srcOffset: offset, data: slice}, deps)
+}
+
+// AddHost adds a node running fn on the host in its own goroutine once
+// deps have completed. If fn returns an error the event of the node
+// reports ErrGraphHostCallback and the commands depending on it are
+// terminated.
+func (g *Graph) AddHost(fn func() error, deps ...*GraphNode) *GraphNode {
+	return g.add(&GraphNode{kind: graphHost, host: fn}, deps)
+}
+
+// SetArgs replaces the arguments of a kernel node for the next execution.
+func (n *GraphNode) SetArgs(args ...interface{}) {
+	n.args = args
+}
+
+// SetData replaces the slice of a read or write node for the next
+// execution.
+func (n *GraphNode) SetData(slice interface{}) {
+	n.data = slice
+}
+
+// SetQueue selects the queue (by its index in the queues passed to
+// Execute) the node is enqueued on. By default nodes use the queue of
+// their first dependency, and nodes without dependencies are distributed
+// over the queues.
+func (n *GraphNode) SetQueue(index int) {
+	n.queue = index
+}
+
+// Event returns the event of the node from the last execution.
+func (n *GraphNode) Event() *Event {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.event
+}
+
+// Err returns the error returned by the callback of a host node in the
+// last execution.
+func (n *GraphNode) Err() error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.err
+}
+
+// Execute enqueues the nodes of the graph on the queues, which must belong
+// to the context of the graph, and flushes them. Out-of-order queues allow
+// independent nodes to run concurrently. It returns an event that
+// completes when all nodes have completed. A graph must not be executed
+// again before the previous execution has been enqueued (i.e. Execute has
+// returned).
+func (g *Graph) Execute(queues ...*CommandQueue) (*Event, error) {
+	if len(queues) == 0 {
+		return nil, errors.New("cl: Graph.Execute requires at least one queue")
+	}
+	queueOf := make(map[*GraphNode]int, len(g.nodes))
+	events := make(map[*GraphNode]*Event, len(g.nodes))
+	dependents := make(map[*GraphNode]bool, len(g.nodes))
+	next := 0
+	for _, n := range g.nodes {
+		q := n.queue
+		switch {
+		case q >= len(queues):
+			return nil, fmt.Errorf("cl: graph node uses queue %d of %d", q, len(queues))
+		case q < 0 && len(n.deps) > 0:
+			q = queueOf[n.deps[0]]
+		case q < 0:
+			q = next % len(queues)
+			next++
+		}
+		queueOf[n] = q
+		waitList := make([]*Event, 0, len(n.deps))
+		for _, d := range n.deps {
+			ev, ok := events[d]
+			if !ok {
+				return nil, errors.New("cl: graph node depends on a node of another graph")
+			}
+			waitList = append(waitList, ev)
+			dependents[d] = true
+		}
+		ev, err := g.enqueue(n, queues[q], waitList)
+		if err != nil {
+			return nil, err
+		}
+		n.mu.Lock()
+		n.event = ev
+		n.mu.Unlock()
+		events[n] = ev
+	}
+	for _, q := range queues {
+		if err := q.Flush(); err != nil {
+			return nil, err
+		}
+	}
+
+	var sinks []*Event
+	for _, n := range g.nodes {
+		if !dependents[n] {
+			sinks = append(sinks, events[n])
+		}
+	}
+	if len(sinks) == 1 {
+		return sinks[0], nil
+	}
+	done, err := g.ctx.CreateUserEvent()
+	if err != nil {
+		return nil, err
+	}
+	go func() {
+		status := int(CommmandExecStatusComplete)
+		for _, ev := range sinks {
+			<-ev.Done()
+			if err := ev.Err(); err != nil && status == int(CommmandExecStatusComplete) {
+				status = errorStatus(err)
+			}
+		}
+		done.SetUserEventStatus(status)
+	}()
+	return done, nil
+}
+
+func (g *Graph) enqueue(n *GraphNode, q *CommandQueue, waitList []*Event) (*Event, error) {
+	switch n.kind {
+	case graphKernel:
+		if err := n.kernel.SetArgs(n.args...); err != nil {
+			return nil, err
+		}
+		r := n.ndRange
+		return q.EnqueueNDRangeKernel(n.kernel, r.Offset, r.Global, r.Local, waitList)
+	case graphCopy:
+		return q.EnqueueCopyBuffer(n.src, n.dst, n.srcOffset, n.dstOffset, n.size, waitList)
+	case graphWrite:
+		return q.EnqueueWriteBufferSlice(n.dst, false, n.dstOffset, n.data, waitList)
+	case graphRead:
+		return q.EnqueueReadBufferSlice(n.src, false, n.srcOffset, n.data, waitList)
+	case graphHost:
+		ev, err := g.ctx.CreateUserEvent()
+		if err != nil {
+			return nil, err
+		}
+		go n.runHost(ev, waitList)
+		return ev, nil
+	}
+	return nil, fmt.Errorf("cl: unknown graph node kind %d", n.kind)
+}
+
+// runHost waits for waitList, runs the callback of the host node and
+// completes the user event ev.
+func (n *GraphNode) runHost(ev *Event, waitList []*Event) {
+	var err error
+	for _, w := range waitList {
+		<-w.Done()
+		if werr := w.Err(); werr != nil && err == nil {
+			err = werr
+		}
+	}
+	status := int(CommmandExecStatusComplete)
+	if err != nil {
+		status = errorStatus(err)
+	} else if err = n.host(); err != nil {
+		status = errorStatus(ErrGraphHostCallback)
+	}
+	n.mu.Lock()
+	n.err = err
+	n.mu.Unlock()
+	ev.SetUserEventStatus(status)
+}
+
+// errorStatus returns the negative execution status corresponding to err.
+func errorStatus(err error) int {
+	if code, ok := err.(ErrOther); ok {
+		return int(code)
+	}
+	for code, e := range errorMap {
+		if e == err {
+			return int(code)
+		}
+	}
+	return graphHostCallbackStatus
+}
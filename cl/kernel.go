@@ -4,7 +4,13 @@ package cl
 import "C"
 
 import (
+	"encoding/binary"
+	"errors"
 	"fmt"
+	"reflect"
+	"runtime"
+	"sort"
+	"strings"
 	"unsafe"
 )
 
@@ -17,9 +23,79 @@ func (e ErrUnsupportedArgumentType) Error() string {
 	return fmt.Sprintf("cl: unsupported argument type for index %d: %+v", e.Index, e.Value)
 }
 
+// KernelArgAddressQualifier is the address space an argument pointer
+// points to (or private for arguments passed by value).
+type KernelArgAddressQualifier int
+
+// KernelArgAccessQualifier is the access qualifier of an image argument.
+type KernelArgAccessQualifier int
+
+// KernelArgTypeQualifier is a bitfield of the type qualifiers of an argument.
+type KernelArgTypeQualifier int
+
+var (
+	kernelArgAddressQualifierNameMap = map[KernelArgAddressQualifier]string{}
+	kernelArgAccessQualifierNameMap  = map[KernelArgAccessQualifier]string{}
+	kernelArgTypeQualifierNameMap    = map[KernelArgTypeQualifier]string{}
+)
+
+func (q KernelArgAddressQualifier) String() string {
+	name := kernelArgAddressQualifierNameMap[q]
+	if name == "" {
+		name = fmt.Sprintf("Unknown(%x)", int(q))
+	}
+	return name
+}
+
+func (q KernelArgAccessQualifier) String() string {
+	name := kernelArgAccessQualifierNameMap[q]
+	if name == "" {
+		name = fmt.Sprintf("Unknown(%x)", int(q))
+	}
+	return name
+}
+
+func (q KernelArgTypeQualifier) String() string {
+	var parts []string
+	for bit, name := range kernelArgTypeQualifierNameMap {
+		if q&bit != 0 {
+			parts = append(parts, name)
+		}
+	}
+	if parts == nil {
+		return "None"
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, "|")
+}
+
+// KernelArgInfo describes an argument of a kernel as returned by clGetKernelArgInfo.
+type KernelArgInfo struct {
+	Name             string
+	TypeName         string // e.g. "float*" or "uint"
+	AddressQualifier KernelArgAddressQualifier
+	AccessQualifier  KernelArgAccessQualifier
+	TypeQualifier    KernelArgTypeQualifier
+}
+
+// ErrDoubleUnsupported is returned when setting a float64 argument on a
+// kernel whose devices don't support double precision floating-point.
+var ErrDoubleUnsupported = errors.New("cl: double precision floating-point not supported by device")
+
+// cloneKernel is set by the version specific files when the package is
+// built against OpenCL 2.1 or later.
+var cloneKernel func(k *Kernel) (C.cl_kernel, error)
+
 type Kernel struct {
 	clKernel C.cl_kernel
 	name     string
+	program  *Program
+
+	// Device properties used to set arguments, cached on first use
+	addressBits     int
+	doubleSupported *bool
+	byteOrder       binary.ByteOrder
+	args            []KernelArgInfo
 }
 
 type LocalBuffer int
@@ -35,7 +111,88 @@ func (k *Kernel) Release() {
 	releaseKernel(k)
 }
 
+// Clone returns a copy of the kernel that can be used independently of k,
+// e.g. to set arguments and enqueue it from another goroutine. When built
+// with the cl21 or cl22 tag and all devices of the kernel support OpenCL
+// 2.1, clCloneKernel is used and the clone starts with the arguments
+// currently set on k. Otherwise the kernel is created again from its
+// program and no arguments are set.
+func (k *Kernel) Clone() (*Kernel, error) {
+	var clone *Kernel
+	if cloneKernel != nil && k.versionAtLeast(2, 1) {
+		clKernel, err := cloneKernel(k)
+		if err != nil {
+			return nil, err
+		}
+		if clKernel == nil {
+			return nil, ErrUnknown
+		}
+		clone = &Kernel{clKernel: clKernel, name: k.name, program: k.program}
+		runtime.SetFinalizer(clone, releaseKernel)
+	} else {
+		program, err := k.Program()
+		if err != nil {
+			return nil, err
+		}
+		if clone, err = program.CreateKernel(k.name); err != nil {
+			return nil, err
+		}
+	}
+	clone.addressBits = k.addressBits
+	clone.doubleSupported = k.doubleSupported
+	clone.byteOrder = k.byteOrder
+	clone.args = k.args
+	return clone, nil
+}
+
+// ErrArgumentCount is returned by SetArgs when the number of arguments
+// doesn't match the kernel.
+type ErrArgumentCount struct {
+	Kernel   string
+	Expected int
+	Given    int
+}
+
+func (e ErrArgumentCount) Error() string {
+	return fmt.Sprintf("cl: kernel %s takes %d arguments, %d given", e.Kernel, e.Expected, e.Given)
+}
+
+// ErrArgumentMismatch is returned by SetArgs when an argument doesn't
+// match the type declared by the kernel.
+type ErrArgumentMismatch struct {
+	Kernel   string
+	Index    int
+	Name     string
+	Expected string // OpenCL C type of the argument (e.g. "__global float*")
+	Given    string // Go type of the value
+}
+
+func (e ErrArgumentMismatch) Error() string {
+	return fmt.Sprintf("cl: kernel %s argument %d (%s) expects %s, %s given", e.Kernel, e.Index, e.Name, e.Expected, e.Given)
+}
+
+// SetArgs sets all arguments of the kernel. The number of arguments must
+// match the kernel. If the program was built with -cl-kernel-arg-info the
+// arguments are also checked against the declared types: buffers for
+// global and constant pointers, LocalBuffer for local pointers, and a
+// matching Go type for arguments passed by value (integers only need to
+// match in size, vectors in element type and width). Mismatches are
+// reported as ErrArgumentCount or ErrArgumentMismatch before any argument is
+// set. Values for types that can't be checked return an error; use SetArg
+// for them.
 func (k *Kernel) SetArgs(args ...interface{}) error {
+	numArgs, err := k.NumArgs()
+	if err != nil {
+		return err
+	}
+	if numArgs != len(args) {
+		return ErrArgumentCount{Kernel: k.name, Expected: numArgs, Given: len(args)}
+	}
+	for index, arg := range args {
+		if err := k.checkArgType(index, arg); err != nil {
+			return err
+		}
+	}
 	for index, arg := range args {
 		if err := k.SetArg(index, arg); err != nil {
 			return err
@@ -44,23 +201,155 @@ func (k *Kernel) SetArgs(args ...interface{}) error {
 	return nil
 }
 
+// ErrArgumentBinding is returned by SetArgByName and Bind when names don't
+// match the arguments of the kernel.
+type ErrArgumentBinding struct {
+	Kernel    string
+	Unknown   []string // names that aren't arguments of the kernel
+	Unbound   []string // kernel arguments without a value
+	Duplicate []string // kernel arguments bound by more than one field
+}
+
+func (e ErrArgumentBinding) Error() string {
+	var parts []string
+	if len(e.Unknown) != 0 {
+		parts = append(parts, "unknown arguments "+strings.Join(e.Unknown, ", "))
+	}
+	if len(e.Unbound) != 0 {
+		parts = append(parts, "unbound arguments "+strings.Join(e.Unbound, ", "))
+	}
+	if len(e.Duplicate) != 0 {
+		parts = append(parts, "arguments bound more than once "+strings.Join(e.Duplicate, ", "))
+	}
+	return fmt.Sprintf("cl: kernel %s: %s", e.Kernel, strings.Join(parts, "; "))
+}
+
+// SetArgByName sets the argument with the given name. It requires the
+// program to be built with -cl-kernel-arg-info (see
+// BuildOptions.KernelArgInfo).
+func (k *Kernel) SetArgByName(name string, value interface{}) error {
+	infos, err := k.argInfos()
+	if err != nil {
+		return err
+	}
+	for index, info := range infos {
+		if info.Name == name {
+			if err := k.checkArgType(index, value); err != nil {
+				return err
+			}
+			return k.SetArg(index, value)
+		}
+	}
+	return ErrArgumentBinding{Kernel: k.name, Unknown: []string{name}}
+}
+
+// Bind sets the arguments of the kernel from the fields of the struct v
+// (or pointer to struct). Fields are matched to arguments by the name in
+// their `cl:"name"` tag or else by the field name. Fields tagged with "-"
+// and unexported fields are ignored. All arguments of the kernel must be
+// bound by exactly one field and every field must match an argument,
+// otherwise ErrArgumentBinding is returned before any argument is set. It
+// requires the program to be built with -cl-kernel-arg-info (see
+// BuildOptions.KernelArgInfo).
+func (k *Kernel) Bind(v interface{}) error {
+	rv := reflect.Indirect(reflect.ValueOf(v))
+	if rv.Kind() != reflect.Struct {
+		return fmt.Errorf("cl: Bind requires a struct, got %T", v)
+	}
+	infos, err := k.argInfos()
+	if err != nil {
+		return err
+	}
+	indexes := make(map[string]int, len(infos))
+	for index, info := range infos {
+		indexes[info.Name] = index
+	}
+	values := make([]interface{}, len(infos))
+	bound := make([]bool, len(infos))
+	duplicate := make([]bool, len(infos))
+	var bindErr ErrArgumentBinding
+	t := rv.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+		tag, err := parseStructTag(f.Tag.Get("cl"))
+		if err != nil {
+			return fmt.Errorf("cl: invalid tag on field %s.%s: %s", t, f.Name, err)
+		}
+		if tag.skip {
+			continue
+		}
+		name := f.Name
+		if tag.name != "" {
+			name = tag.name
+		}
+		index, ok := indexes[name]
+		if !ok {
+			bindErr.Unknown = append(bindErr.Unknown, name)
+			continue
+		}
+		if bound[index] {
+			if !duplicate[index] {
+				duplicate[index] = true
+				bindErr.Duplicate = append(bindErr.Duplicate, name)
+			}
+			continue
+		}
+		values[index] = rv.Field(i).Interface()
+		bound[index] = true
+	}
+	for index, info := range infos {
+		if !bound[index] {
+			bindErr.Unbound = append(bindErr.Unbound, info.Name)
+		}
+	}
+	if bindErr.Unknown != nil || bindErr.Unbound != nil || bindErr.Duplicate != nil {
+		bindErr.Kernel = k.name
+		return bindErr
+	}
+	return k.SetArgs(values...)
+}
+
 func (k *Kernel) SetArg(index int, arg interface{}) error {
 	switch val := arg.(type) {
 	case uint8:
 		return k.SetArgUint8(index, val)
 	case int8:
 		return k.SetArgInt8(index, val)
+	case uint16:
+		return k.SetArgUint16(index, val)
+	case int16:
+		return k.SetArgInt16(index, val)
 	case uint32:
 		return k.SetArgUint32(index, val)
 	case int32:
 		return k.SetArgInt32(index, val)
+	case uint64:
+		return k.SetArgUint64(index, val)
+	case int64:
+		return k.SetArgInt64(index, val)
+	case uint:
+		return k.SetArgUint(index, val)
+	case int:
+		return k.SetArgInt(index, val)
+	case bool:
+		return k.SetArgBool(index, val)
 	case float32:
 		return k.SetArgFloat32(index, val)
+	case float64:
+		return k.SetArgFloat64(index, val)
 	case *MemObject:
 		return k.SetArgBuffer(index, val)
 	case LocalBuffer:
 		return k.SetArgLocal(index, int(val))
+	case Vector:
+		return k.SetArgVector(index, val)
 	default:
+		if reflect.Indirect(reflect.ValueOf(arg)).Kind() == reflect.Struct {
+			return k.SetArgStruct(index, arg)
+		}
 		return ErrUnsupportedArgumentType{Index: index, Value: arg}
 	}
 }
@@ -73,6 +362,16 @@ func (k *Kernel) SetArgFloat32(index int, val float32) error {
 	return k.SetArgUnsafe(index, int(unsafe.Sizeof(val)), unsafe.Pointer(&val))
 }
 
+// SetArgFloat64 sets a double argument. It returns ErrDoubleUnsupported if
+// any of the devices of the kernel's program report no double precision
+// floating-point capability.
+func (k *Kernel) SetArgFloat64(index int, val float64) error {
+	if !k.supportsDouble() {
+		return ErrDoubleUnsupported
+	}
+	return k.SetArgUnsafe(index, int(unsafe.Sizeof(val)), unsafe.Pointer(&val))
+}
+
 func (k *Kernel) SetArgInt8(index int, val int8) error {
 	return k.SetArgUnsafe(index, int(unsafe.Sizeof(val)), unsafe.Pointer(&val))
 }
@@ -81,6 +380,14 @@ func (k *Kernel) SetArgUint8(index int, val uint8) error {
 	return k.SetArgUnsafe(index, int(unsafe.Sizeof(val)), unsafe.Pointer(&val))
 }
 
+func (k *Kernel) SetArgInt16(index int, val int16) error {
+	return k.SetArgUnsafe(index, int(unsafe.Sizeof(val)), unsafe.Pointer(&val))
+}
+
+func (k *Kernel) SetArgUint16(index int, val uint16) error {
+	return k.SetArgUnsafe(index, int(unsafe.Sizeof(val)), unsafe.Pointer(&val))
+}
+
 func (k *Kernel) SetArgInt32(index int, val int32) error {
 	return k.SetArgUnsafe(index, int(unsafe.Sizeof(val)), unsafe.Pointer(&val))
 }
@@ -89,6 +396,50 @@ func (k *Kernel) SetArgUint32(index int, val uint32) error {
 	return k.SetArgUnsafe(index, int(unsafe.Sizeof(val)), unsafe.Pointer(&val))
 }
 
+func (k *Kernel) SetArgInt64(index int, val int64) error {
+	return k.SetArgUnsafe(index, int(unsafe.Sizeof(val)), unsafe.Pointer(&val))
+}
+
+func (k *Kernel) SetArgUint64(index int, val uint64) error {
+	return k.SetArgUnsafe(index, int(unsafe.Sizeof(val)), unsafe.Pointer(&val))
+}
+
+// SetArgInt sets an argument with the width of the device's size_t (32 or
+// 64 bits as reported by Device.AddressBits).
+func (k *Kernel) SetArgInt(index int, val int) error {
+	bits, err := k.deviceAddressBits()
+	if err != nil {
+		return err
+	}
+	if bits == 32 {
+		return k.SetArgInt32(index, int32(val))
+	}
+	return k.SetArgInt64(index, int64(val))
+}
+
+// SetArgUint sets an argument with the width of the device's size_t (32
+// or 64 bits as reported by Device.AddressBits).
+func (k *Kernel) SetArgUint(index int, val uint) error {
+	bits, err := k.deviceAddressBits()
+	if err != nil {
+		return err
+	}
+	if bits == 32 {
+		return k.SetArgUint32(index, uint32(val))
+	}
+	return k.SetArgUint64(index, uint64(val))
+}
+
+// SetArgBool sets a boolean argument as a 32-bit 0 or 1 (i.e. cl_bool).
+// OpenCL C doesn't allow bool kernel arguments so the kernel should
+// declare the argument as int or uint.
+func (k *Kernel) SetArgBool(index int, val bool) error {
+	if val {
+		return k.SetArgUint32(index, 1)
+	}
+	return k.SetArgUint32(index, 0)
+}
+
 func (k *Kernel) SetArgLocal(index int, size int) error {
 	return k.SetArgUnsafe(index, size, nil)
 }
@@ -109,8 +460,176 @@ func (k *Kernel) WorkGroupSize(device *Device) (int, error) {
 	return int(size), toError(err)
 }
 
+// LocalMemSize returns the amount of local memory in bytes used by the
+// kernel on the device. This includes local memory declared in the kernel
+// and, if set, the size of the local memory arguments (LocalBuffer).
+func (k *Kernel) LocalMemSize(device *Device) (int64, error) {
+	var size C.cl_ulong
+	err := C.clGetKernelWorkGroupInfo(k.clKernel, device.nullableId(), C.CL_KERNEL_LOCAL_MEM_SIZE, C.size_t(unsafe.Sizeof(size)), unsafe.Pointer(&size), nil)
+	return int64(size), toError(err)
+}
+
+// PrivateMemSize returns the minimum amount of private memory in bytes
+// used by each work-item of the kernel on the device.
+func (k *Kernel) PrivateMemSize(device *Device) (int64, error) {
+	var size C.cl_ulong
+	err := C.clGetKernelWorkGroupInfo(k.clKernel, device.nullableId(), C.CL_KERNEL_PRIVATE_MEM_SIZE, C.size_t(unsafe.Sizeof(size)), unsafe.Pointer(&size), nil)
+	return int64(size), toError(err)
+}
+
+// CompileWorkGroupSize returns the work-group size specified by the
+// __attribute__((reqd_work_group_size(X, Y, Z))) qualifier of the kernel,
+// or (0, 0, 0) if it isn't specified.
+func (k *Kernel) CompileWorkGroupSize(device *Device) ([3]int, error) {
+	var size [3]C.size_t
+	err := C.clGetKernelWorkGroupInfo(k.clKernel, device.nullableId(), C.CL_KERNEL_COMPILE_WORK_GROUP_SIZE, C.size_t(unsafe.Sizeof(size)), unsafe.Pointer(&size[0]), nil)
+	return [3]int{int(size[0]), int(size[1]), int(size[2])}, toError(err)
+}
+
 func (k *Kernel) NumArgs() (int, error) {
 	var num C.cl_uint
 	err := C.clGetKernelInfo(k.clKernel, C.CL_KERNEL_NUM_ARGS, C.size_t(unsafe.Sizeof(num)), unsafe.Pointer(&num), nil)
 	return int(num), toError(err)
 }
+
+func (k *Kernel) getInfoString(param C.cl_kernel_info) (string, error) {
+	var strN C.size_t
+	if err := C.clGetKernelInfo(k.clKernel, param, 0, nil, &strN); err != C.CL_SUCCESS {
+		return "", toError(err)
+	}
+	if strN <= 1 {
+		return "", nil
+	}
+	strC := make([]byte, strN)
+	if err := C.clGetKernelInfo(k.clKernel, param, strN, unsafe.Pointer(&strC[0]), nil); err != C.CL_SUCCESS {
+		return "", toError(err)
+	}
+	// Strip the terminating NUL
+	return string(strC[:strN-1]), nil
+}
+
+// FunctionName returns the name of the kernel function.
+func (k *Kernel) FunctionName() (string, error) {
+	return k.getInfoString(C.CL_KERNEL_FUNCTION_NAME)
+}
+
+// ReferenceCount returns the reference count of the kernel. The value
+// should be considered immediately stale and is mainly useful for
+// identifying leaks.
+func (k *Kernel) ReferenceCount() (int, error) {
+	var count C.cl_uint
+	err := C.clGetKernelInfo(k.clKernel, C.CL_KERNEL_REFERENCE_COUNT, C.size_t(unsafe.Sizeof(count)), unsafe.Pointer(&count), nil)
+	return int(count), toError(err)
+}
+
+// Context returns the context associated with the kernel.
+func (k *Kernel) Context() (*Context, error) {
+	var clContext C.cl_context
+	if err := C.clGetKernelInfo(k.clKernel, C.CL_KERNEL_CONTEXT, C.size_t(unsafe.Sizeof(clContext)), unsafe.Pointer(&clContext), nil); err != C.CL_SUCCESS {
+		return nil, toError(err)
+	}
+	return retainContext(clContext)
+}
+
+// Program returns the program the kernel was created from.
+func (k *Kernel) Program() (*Program, error) {
+	var clProgram C.cl_program
+	if err := C.clGetKernelInfo(k.clKernel, C.CL_KERNEL_PROGRAM, C.size_t(unsafe.Sizeof(clProgram)), unsafe.Pointer(&clProgram), nil); err != C.CL_SUCCESS {
+		return nil, toError(err)
+	}
+	if k.program != nil && k.program.clProgram == clProgram {
+		return k.program, nil
+	}
+	return retainProgram(clProgram)
+}
+
+// versionAtLeast reports whether all devices of the kernel's program
+// support OpenCL major.minor or later.
+func (k *Kernel) versionAtLeast(major, minor int) bool {
+	devices := k.devices()
+	for _, d := range devices {
+		if !d.versionAtLeast(major, minor) {
+			return false
+		}
+	}
+	return len(devices) > 0
+}
+
+func (k *Kernel) devices() []*Device {
+	if k.program == nil {
+		return nil
+	}
+	return k.program.devices
+}
+
+// deviceAddressBits returns the address width shared by all devices of the
+// kernel's program.
+func (k *Kernel) deviceAddressBits() (int, error) {
+	if k.addressBits != 0 {
+		return k.addressBits, nil
+	}
+	bits := 0
+	for _, d := range k.devices() {
+		b := d.AddressBits()
+		if bits != 0 && b != bits {
+			return 0, fmt.Errorf("cl: kernel %s has devices with different address widths (%d and %d bits)", k.name, bits, b)
+		}
+		bits = b
+	}
+	if bits == 0 {
+		return 0, fmt.Errorf("cl: unable to determine the device address width for kernel %s", k.name)
+	}
+	k.addressBits = bits
+	return bits, nil
+}
+
+// supportsDouble reports whether all devices of the kernel's program
+// support double precision floating-point.
+func (k *Kernel) supportsDouble() bool {
+	if k.doubleSupported == nil {
+		supported := true
+		for _, d := range k.devices() {
+			if d.DoubleFPConfig() == 0 {
+				supported = false
+			}
+		}
+		k.doubleSupported = &supported
+	}
+	return *k.doubleSupported
+}
+
+// deviceByteOrder returns the byte order shared by all devices of the
+// kernel's program.
+func (k *Kernel) deviceByteOrder() (binary.ByteOrder, error) {
+	if k.byteOrder != nil {
+		return k.byteOrder, nil
+	}
+	var order binary.ByteOrder
+	for _, d := range k.devices() {
+		var o binary.ByteOrder = binary.BigEndian
+		if d.EndianLittle() {
+			o = binary.LittleEndian
+		}
+		if order != nil && o != order {
+			return nil, fmt.Errorf("cl: kernel %s has devices with different byte orders", k.name)
+		}
+		order = o
+	}
+	if order == nil {
+		return nil, fmt.Errorf("cl: unable to determine the device byte order for kernel %s", k.name)
+	}
+	k.byteOrder = order
+	return order, nil
+}
+
+// argInfos returns the (cached) info for all arguments of the kernel.
+func (k *Kernel) argInfos() ([]KernelArgInfo, error) {
+	if k.args == nil {
+		args, err := k.Args()
+		if err != nil {
+			return nil, err
+		}
+		k.args = args
+	}
+	return k.args, nil
+}
@@ -5,3 +5,23 @@ package cl
 func (k *Kernel) ArgName(index int) (string, error) {
 	return "", ErrUnsupported
 }
+
+func (k *Kernel) Attributes() (string, error) {
+	return "", ErrUnsupported
+}
+
+func (k *Kernel) GlobalWorkSize(device *Device) ([3]int, error) {
+	return [3]int{}, ErrUnsupported
+}
+
+func (k *Kernel) ArgInfo(index int) (KernelArgInfo, error) {
+	return KernelArgInfo{}, ErrUnsupported
+}
+
+func (k *Kernel) Args() ([]KernelArgInfo, error) {
+	return nil, ErrUnsupported
+}
+
+func (k *Kernel) checkArgType(index int, arg interface{}) error {
+	return nil
+}
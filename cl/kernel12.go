@@ -3,14 +3,282 @@
 package cl
 
 // #import "cl.h"
+//
+// #ifndef CL_KERNEL_ARG_TYPE_PIPE
+// #define CL_KERNEL_ARG_TYPE_PIPE (1 << 3)
+// #endif
 import "C"
-import "unsafe"
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"unsafe"
+)
 
-func (k *Kernel) ArgName(index int) (string, error) {
-	var strC [1024]byte
+const (
+	KernelArgAddressGlobal   KernelArgAddressQualifier = C.CL_KERNEL_ARG_ADDRESS_GLOBAL
+	KernelArgAddressLocal    KernelArgAddressQualifier = C.CL_KERNEL_ARG_ADDRESS_LOCAL
+	KernelArgAddressConstant KernelArgAddressQualifier = C.CL_KERNEL_ARG_ADDRESS_CONSTANT
+	KernelArgAddressPrivate  KernelArgAddressQualifier = C.CL_KERNEL_ARG_ADDRESS_PRIVATE
+)
+
+const (
+	KernelArgAccessReadOnly  KernelArgAccessQualifier = C.CL_KERNEL_ARG_ACCESS_READ_ONLY
+	KernelArgAccessWriteOnly KernelArgAccessQualifier = C.CL_KERNEL_ARG_ACCESS_WRITE_ONLY
+	KernelArgAccessReadWrite KernelArgAccessQualifier = C.CL_KERNEL_ARG_ACCESS_READ_WRITE
+	KernelArgAccessNone      KernelArgAccessQualifier = C.CL_KERNEL_ARG_ACCESS_NONE
+)
+
+const (
+	KernelArgTypeNone     KernelArgTypeQualifier = C.CL_KERNEL_ARG_TYPE_NONE
+	KernelArgTypeConst    KernelArgTypeQualifier = C.CL_KERNEL_ARG_TYPE_CONST
+	KernelArgTypeRestrict KernelArgTypeQualifier = C.CL_KERNEL_ARG_TYPE_RESTRICT
+	KernelArgTypeVolatile KernelArgTypeQualifier = C.CL_KERNEL_ARG_TYPE_VOLATILE
+	KernelArgTypePipe     KernelArgTypeQualifier = C.CL_KERNEL_ARG_TYPE_PIPE // OpenCL 2.0
+)
+
+func init() {
+	kernelArgAddressQualifierNameMap[KernelArgAddressGlobal] = "Global"
+	kernelArgAddressQualifierNameMap[KernelArgAddressLocal] = "Local"
+	kernelArgAddressQualifierNameMap[KernelArgAddressConstant] = "Constant"
+	kernelArgAddressQualifierNameMap[KernelArgAddressPrivate] = "Private"
+	kernelArgAccessQualifierNameMap[KernelArgAccessReadOnly] = "ReadOnly"
+	kernelArgAccessQualifierNameMap[KernelArgAccessWriteOnly] = "WriteOnly"
+	kernelArgAccessQualifierNameMap[KernelArgAccessReadWrite] = "ReadWrite"
+	kernelArgAccessQualifierNameMap[KernelArgAccessNone] = "None"
+	kernelArgTypeQualifierNameMap[KernelArgTypeConst] = "Const"
+	kernelArgTypeQualifierNameMap[KernelArgTypeRestrict] = "Restrict"
+	kernelArgTypeQualifierNameMap[KernelArgTypeVolatile] = "Volatile"
+	kernelArgTypeQualifierNameMap[KernelArgTypePipe] = "Pipe"
+}
+
+// Attributes returns the attributes specified using __attribute__ in the
+// kernel declaration, separated by spaces.
+func (k *Kernel) Attributes() (string, error) {
+	return k.getInfoString(C.CL_KERNEL_ATTRIBUTES)
+}
+
+// GlobalWorkSize returns the maximum global size that can be used to
+// execute the kernel on the device. It is only valid for custom devices
+// and built-in kernels.
+func (k *Kernel) GlobalWorkSize(device *Device) ([3]int, error) {
+	var size [3]C.size_t
+	err := C.clGetKernelWorkGroupInfo(k.clKernel, device.nullableId(), C.CL_KERNEL_GLOBAL_WORK_SIZE, C.size_t(unsafe.Sizeof(size)), unsafe.Pointer(&size[0]), nil)
+	return [3]int{int(size[0]), int(size[1]), int(size[2])}, toError(err)
+}
+
+func (k *Kernel) getArgInfoString(index int, param C.cl_kernel_arg_info) (string, error) {
 	var strN C.size_t
-	if err := C.clGetKernelArgInfo(k.clKernel, C.cl_uint(index), C.CL_KERNEL_ARG_NAME, 1024, unsafe.Pointer(&strC[0]), &strN); err != C.CL_SUCCESS {
+	if err := C.clGetKernelArgInfo(k.clKernel, C.cl_uint(index), param, 0, nil, &strN); err != C.CL_SUCCESS {
 		return "", toError(err)
 	}
-	return string(strC[:strN]), nil
+	if strN <= 1 {
+		return "", nil
+	}
+	strC := make([]byte, strN)
+	if err := C.clGetKernelArgInfo(k.clKernel, C.cl_uint(index), param, strN, unsafe.Pointer(&strC[0]), nil); err != C.CL_SUCCESS {
+		return "", toError(err)
+	}
+	// Strip the terminating NUL
+	return string(strC[:strN-1]), nil
+}
+
+func (k *Kernel) getArgInfoUint(index int, param C.cl_kernel_arg_info) (uint, error) {
+	var val C.cl_uint
+	if err := C.clGetKernelArgInfo(k.clKernel, C.cl_uint(index), param, C.size_t(unsafe.Sizeof(val)), unsafe.Pointer(&val), nil); err != C.CL_SUCCESS {
+		return 0, toError(err)
+	}
+	return uint(val), nil
+}
+
+// ArgName returns the name of the argument at index. It returns
+// ErrKernelArgInfoNotAvailable if the program wasn't built with
+// -cl-kernel-arg-info.
+func (k *Kernel) ArgName(index int) (string, error) {
+	return k.getArgInfoString(index, C.CL_KERNEL_ARG_NAME)
+}
+
+// ArgInfo returns the name, type and qualifiers of the argument at index.
+// It returns ErrKernelArgInfoNotAvailable if the program wasn't built with
+// -cl-kernel-arg-info (see BuildOptions.KernelArgInfo).
+func (k *Kernel) ArgInfo(index int) (KernelArgInfo, error) {
+	var info KernelArgInfo
+	var err error
+	if info.Name, err = k.getArgInfoString(index, C.CL_KERNEL_ARG_NAME); err != nil {
+		return info, err
+	}
+	if info.TypeName, err = k.getArgInfoString(index, C.CL_KERNEL_ARG_TYPE_NAME); err != nil {
+		return info, err
+	}
+	addr, err := k.getArgInfoUint(index, C.CL_KERNEL_ARG_ADDRESS_QUALIFIER)
+	if err != nil {
+		return info, err
+	}
+	info.AddressQualifier = KernelArgAddressQualifier(addr)
+	access, err := k.getArgInfoUint(index, C.CL_KERNEL_ARG_ACCESS_QUALIFIER)
+	if err != nil {
+		return info, err
+	}
+	info.AccessQualifier = KernelArgAccessQualifier(access)
+	var typeQual C.cl_kernel_arg_type_qualifier
+	if err := C.clGetKernelArgInfo(k.clKernel, C.cl_uint(index), C.CL_KERNEL_ARG_TYPE_QUALIFIER, C.size_t(unsafe.Sizeof(typeQual)), unsafe.Pointer(&typeQual), nil); err != C.CL_SUCCESS {
+		return info, toError(err)
+	}
+	info.TypeQualifier = KernelArgTypeQualifier(typeQual)
+	return info, nil
+}
+
+// Args returns the info for all arguments of the kernel. It returns
+// ErrKernelArgInfoNotAvailable if the program wasn't built with
+// -cl-kernel-arg-info (see BuildOptions.KernelArgInfo).
+func (k *Kernel) Args() ([]KernelArgInfo, error) {
+	n, err := k.NumArgs()
+	if err != nil {
+		return nil, err
+	}
+	args := make([]KernelArgInfo, n)
+	for i := range args {
+		if args[i], err = k.ArgInfo(i); err != nil {
+			return nil, err
+		}
+	}
+	return args, nil
+}
+
+// checkArgType checks an argument against the declared type of the kernel
+// argument at index if the arg info is available.
+func (k *Kernel) checkArgType(index int, arg interface{}) error {
+	infos, err := k.argInfos()
+	if err == ErrKernelArgInfoNotAvailable {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	info := infos[index]
+	ok, err := k.argMatches(info, arg)
+	if err != nil {
+		return fmt.Errorf("cl: kernel %s argument %d (%s): %s", k.name, index, info.Name, err)
+	}
+	if !ok {
+		return ErrArgumentMismatch{
+			Kernel:   k.name,
+			Index:    index,
+			Name:     info.Name,
+			Expected: argTypeString(info),
+			Given:    fmt.Sprintf("%T", arg),
+		}
+	}
+	return nil
+}
+
+// clIntegerSizes are the sizes of the OpenCL C integer types
+var clIntegerSizes = map[string]int{
+	"char": 1, "uchar": 1, "unsigned char": 1,
+	"short": 2, "ushort": 2, "unsigned short": 2,
+	"int": 4, "uint": 4, "unsigned int": 4,
+	"long": 8, "ulong": 8, "unsigned long": 8,
+}
+
+// argMatches reports whether arg can be used for the kernel argument
+// described by info. Go structs match any type that isn't a built-in type
+// as their layout can't be checked against the name (e.g. of a typedef).
+// It returns an error for other values when the type is unknown.
+func (k *Kernel) argMatches(info KernelArgInfo, arg interface{}) (bool, error) {
+	switch info.AddressQualifier {
+	case KernelArgAddressGlobal, KernelArgAddressConstant:
+		_, ok := arg.(*MemObject)
+		return ok, nil
+	case KernelArgAddressLocal:
+		_, ok := arg.(LocalBuffer)
+		return ok, nil
+	}
+	typeName := strings.TrimSpace(info.TypeName)
+	if strings.HasSuffix(typeName, "*") {
+		// pointers to private memory can't be set from the host
+		return false, nil
+	}
+	if _, ok := arg.(*MemObject); ok {
+		// images, pipes, etc. are passed as memory objects
+		return strings.HasSuffix(typeName, "_t"), nil
+	}
+	if size, ok := clIntegerSizes[typeName]; ok {
+		switch val := arg.(type) {
+		case int8, uint8, int16, uint16, int32, uint32, int64, uint64:
+			return int(reflect.TypeOf(val).Size()) == size, nil
+		case int, uint:
+			bits, err := k.deviceAddressBits()
+			return err == nil && bits/8 == size, nil
+		case bool:
+			return size == 4, nil
+		}
+		return false, nil
+	}
+	switch typeName {
+	case "float":
+		_, ok := arg.(float32)
+		return ok, nil
+	case "double":
+		_, ok := arg.(float64)
+		return ok, nil
+	case "half":
+		return false, nil
+	}
+	if base, width, ok := parseVectorType(typeName); ok {
+		v, ok := arg.(Vector)
+		return ok && v.Len() == width && vectorElemMatches(reflect.TypeOf(v).Elem(), base), nil
+	}
+	if _, ok := arg.(Vector); !ok && reflect.Indirect(reflect.ValueOf(arg)).Kind() == reflect.Struct {
+		return true, nil
+	}
+	return false, fmt.Errorf("can't check %T against unknown type %s", arg, typeName)
+}
+
+// parseVectorType splits an OpenCL C vector type name such as uchar16 into
+// its element type and width.
+func parseVectorType(typeName string) (string, int, bool) {
+	for _, width := range []int{2, 3, 4, 8, 16} {
+		suffix := strconv.Itoa(width)
+		if !strings.HasSuffix(typeName, suffix) {
+			continue
+		}
+		base := strings.TrimSuffix(typeName, suffix)
+		if _, ok := clIntegerSizes[base]; ok || base == "float" || base == "double" || base == "half" {
+			return base, width, true
+		}
+	}
+	return "", 0, false
+}
+
+// vectorElemMatches reports whether the Go element type of a Vector matches
+// the OpenCL C element type base.
+func vectorElemMatches(elem reflect.Type, base string) bool {
+	switch base {
+	case "float":
+		return elem.Kind() == reflect.Float32
+	case "double":
+		return elem.Kind() == reflect.Float64
+	case "half":
+		return false
+	}
+	unsigned := strings.HasPrefix(base, "u")
+	switch elem.Kind() {
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return !unsigned && int(elem.Size()) == clIntegerSizes[base]
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return unsigned && int(elem.Size()) == clIntegerSizes[base]
+	}
+	return false
+}
+
+func argTypeString(info KernelArgInfo) string {
+	switch info.AddressQualifier {
+	case KernelArgAddressGlobal:
+		return "__global " + info.TypeName
+	case KernelArgAddressConstant:
+		return "__constant " + info.TypeName
+	case KernelArgAddressLocal:
+		return "__local " + info.TypeName
+	}
+	return info.TypeName
 }
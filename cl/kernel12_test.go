@@ -0,0 +1,38 @@
+// +build !cl10
+
+package cl
+
+import "testing"
+
+func TestArgMatches(t *testing.T) {
+	type particle struct{ X, Y float32 }
+	cases := []struct {
+		typeName string
+		arg      interface{}
+		matches  bool
+	}{
+		{"float4", Float4{}, true},
+		{"float4", Float8{}, false},
+		{"float4", Int4{}, false},
+		{"float4", float32(1), false},
+		{"float3", Float3{}, true},
+		{"uint16", UInt16{}, true},
+		{"uint16", Int16{}, false},
+		{"unsigned char2", UChar2{}, true},
+		{"half4", Float4{}, false},
+		{"Particle", particle{}, true},
+		{"Particle", &particle{}, true},
+	}
+	k := &Kernel{name: "k"}
+	for _, c := range cases {
+		matches, err := k.argMatches(KernelArgInfo{TypeName: c.typeName}, c.arg)
+		if err != nil {
+			t.Errorf("%s with %T: unexpected error %s", c.typeName, c.arg, err)
+		} else if matches != c.matches {
+			t.Errorf("%s with %T: expected %v, got %v", c.typeName, c.arg, c.matches, matches)
+		}
+	}
+	if _, err := k.argMatches(KernelArgInfo{TypeName: "my_float"}, float32(1)); err == nil {
+		t.Errorf("Expected an error for an unknown type name")
+	}
+}
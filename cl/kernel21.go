@@ -0,0 +1,17 @@
+// +build cl21 cl22
+
+package cl
+
+// #include "cl.h"
+import "C"
+
+func init() {
+	cloneKernel = func(k *Kernel) (C.cl_kernel, error) {
+		var err C.cl_int
+		clKernel := C.clCloneKernel(k.clKernel, &err)
+		if err != C.CL_SUCCESS {
+			return nil, toError(err)
+		}
+		return clKernel, nil
+	}
+}
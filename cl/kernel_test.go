@@ -0,0 +1,43 @@
+package cl
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestKernelBindErrors(t *testing.T) {
+	k := &Kernel{name: "scale", args: []KernelArgInfo{{Name: "input"}, {Name: "output"}, {Name: "factor"}}}
+
+	err := k.SetArgByName("count", uint32(1))
+	expected := ErrArgumentBinding{Kernel: "scale", Unknown: []string{"count"}}
+	if !reflect.DeepEqual(err, expected) {
+		t.Errorf("SetArgByName: expected %v, got %v", expected, err)
+	}
+
+	type args struct {
+		In     *MemObject `cl:"input"`
+		Output *MemObject `cl:"output"`
+		Out    *MemObject `cl:"output"`
+		Count  uint32     `cl:"count"`
+		Host   string     `cl:"-"`
+		cache  int
+	}
+	err = k.Bind(&args{})
+	expected = ErrArgumentBinding{
+		Kernel:    "scale",
+		Unknown:   []string{"count"},
+		Unbound:   []string{"factor"},
+		Duplicate: []string{"output"},
+	}
+	if !reflect.DeepEqual(err, expected) {
+		t.Fatalf("Bind: expected %v, got %v", expected, err)
+	}
+	message := "cl: kernel scale: unknown arguments count; unbound arguments factor; arguments bound more than once output"
+	if err.Error() != message {
+		t.Errorf("expected error message %q, got %q", message, err.Error())
+	}
+
+	if err := k.Bind(1); err == nil {
+		t.Errorf("Bind should reject a non-struct value")
+	}
+}
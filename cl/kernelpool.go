@@ -0,0 +1,69 @@
+package cl
+
+import "sync"
+
+// KernelPool hands out clones of a kernel so that it can be launched from
+// several goroutines concurrently. Kernel arguments are state of the
+// kernel object, so two goroutines sharing a Kernel race between setting
+// the arguments and enqueueing it. OpenCL captures the argument values
+// when the kernel is enqueued, so a kernel from the pool can be reused as
+// soon as EnqueueNDRangeKernel returns.
+type KernelPool struct {
+	mu     sync.Mutex
+	kernel *Kernel
+	free   []*Kernel
+}
+
+// NewKernelPool returns a pool of clones of kernel. The kernel itself is
+// only used as the prototype for Clone and is never handed out.
+func NewKernelPool(kernel *Kernel) *KernelPool {
+	return &KernelPool{kernel: kernel}
+}
+
+// Get returns a kernel from the pool, cloning the prototype if the pool is
+// empty. The kernel is owned by the caller until it's returned with Put.
+func (p *KernelPool) Get() (*Kernel, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if n := len(p.free); n > 0 {
+		k := p.free[n-1]
+		p.free = p.free[:n-1]
+		return k, nil
+	}
+	return p.kernel.Clone()
+}
+
+// Put returns a kernel obtained from Get to the pool.
+func (p *KernelPool) Put(k *Kernel) {
+	p.mu.Lock()
+	p.free = append(p.free, k)
+	p.mu.Unlock()
+}
+
+// EnqueueNDRangeKernel sets args on a kernel from the pool and enqueues it
+// on the queue. The arguments are captured atomically with respect to
+// other goroutines using the pool. See CommandQueue.EnqueueNDRangeKernel
+// for the other parameters.
+func (p *KernelPool) EnqueueNDRangeKernel(q *CommandQueue, args []interface{}, globalWorkOffset, globalWorkSize, localWorkSize []int, eventWaitList []*Event) (*Event, error) {
+	k, err := p.Get()
+	if err != nil {
+		return nil, err
+	}
+	defer p.Put(k)
+	if err := k.SetArgs(args...); err != nil {
+		return nil, err
+	}
+	return q.EnqueueNDRangeKernel(k, globalWorkOffset, globalWorkSize, localWorkSize, eventWaitList)
+}
+
+// Release releases the kernels in the pool. The prototype kernel isn't
+// released. Kernels still held by callers are released when they're
+// garbage collected.
+func (p *KernelPool) Release() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, k := range p.free {
+		k.Release()
+	}
+	p.free = nil
+}
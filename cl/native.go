@@ -0,0 +1,117 @@
+package cl
+
+// #include <stdlib.h>
+// #include "cl.h"
+//
+// extern void goNativeKernel(void *args);
+import "C"
+
+import (
+	"errors"
+	"unsafe"
+)
+
+// NativeKernelFunc is a Go function run by EnqueueNativeKernel. args is the
+// argument block of the command: a host pointer for every memory object,
+// in the order given to EnqueueNativeKernel, followed by the caller's
+// arguments. Use NativeKernelPointer to read the pointers. args and the
+// memory it points to are only valid while the function runs.
+type NativeKernelFunc func(args []byte)
+
+// NativeKernelPointer returns the host pointer of the memory object with
+// the given index from the args passed to a NativeKernelFunc. The caller's
+// arguments start at NativeKernelArgsOffset(n) for n memory objects.
+func NativeKernelPointer(args []byte, index int) unsafe.Pointer {
+	return *(*unsafe.Pointer)(unsafe.Pointer(&args[index*int(unsafe.Sizeof(uintptr(0)))]))
+}
+
+// NativeKernelArgsOffset returns the offset of the caller's arguments in the
+// args passed to a NativeKernelFunc enqueued with memObjects memory objects.
+func NativeKernelArgsOffset(memObjects int) int {
+	return memObjects * int(unsafe.Sizeof(uintptr(0)))
+}
+
+// nativeKernelCall is registered as the callback of a native kernel.
+type nativeKernelCall struct {
+	fn   NativeKernelFunc
+	size int // size of the argument block after the callback id
+}
+
+// EnqueueNativeKernel enqueues a command to run fn on the host as part of
+// the queue, e.g. to interleave host side processing with device commands
+// in queue order. The device of the queue must support
+// ExecCapabilityNativeKernel (usually only CPU devices do). args is
+// copied when the command is enqueued. memObjects must be buffers; the
+// runtime translates them to host pointers in the args passed to fn.
+func (q *CommandQueue) EnqueueNativeKernel(fn NativeKernelFunc, args []byte, memObjects []*MemObject, eventWaitList []*Event) (*Event, error) {
+	for _, mo := range memObjects {
+		var memType C.cl_mem_object_type
+		if err := C.clGetMemObjectInfo(mo.clMem, C.CL_MEM_TYPE, C.size_t(unsafe.Sizeof(memType)), unsafe.Pointer(&memType), nil); err != C.CL_SUCCESS {
+			return nil, toError(err)
+		}
+		if memType != C.CL_MEM_OBJECT_BUFFER {
+			return nil, errors.New("cl: EnqueueNativeKernel only accepts buffers")
+		}
+	}
+
+	// The runtime copies the argument block and replaces the cl_mem
+	// handles at the given locations with host pointers. The block is
+	// laid out as the callback id, a pointer for every memory object and
+	// then args.
+	ptrSize := int(unsafe.Sizeof(uintptr(0)))
+	headerSize := ptrSize + NativeKernelArgsOffset(len(memObjects))
+	blockSize := headerSize + len(args)
+	block := C.malloc(C.size_t(blockSize))
+	defer C.free(block)
+	blockBytes := (*[1 << 30]byte)(block)[:blockSize:blockSize]
+	copy(blockBytes[headerSize:], args)
+
+	id := registerCallback(&nativeKernelCall{fn: fn, size: blockSize - ptrSize})
+	*(*uintptr)(block) = id
+
+	var memList *C.cl_mem
+	var memLocs *unsafe.Pointer
+	if len(memObjects) > 0 {
+		mems := (*C.cl_mem)(C.malloc(C.size_t(len(memObjects)) * C.size_t(unsafe.Sizeof(memObjects[0].clMem))))
+		defer C.free(unsafe.Pointer(mems))
+		locs := (*unsafe.Pointer)(C.malloc(C.size_t(len(memObjects)) * C.size_t(ptrSize)))
+		defer C.free(unsafe.Pointer(locs))
+		memSlice := (*[1 << 20]C.cl_mem)(unsafe.Pointer(mems))[:len(memObjects):len(memObjects)]
+		locSlice := (*[1 << 20]unsafe.Pointer)(unsafe.Pointer(locs))[:len(memObjects):len(memObjects)]
+		for i, mo := range memObjects {
+			memSlice[i] = mo.clMem
+			locSlice[i] = unsafe.Pointer(&blockBytes[ptrSize*(1+i)])
+		}
+		memList = mems
+		memLocs = locs
+	}
+
+	var event C.cl_event
+	err := C.clEnqueueNativeKernel(q.clQueue, (*[0]byte)(C.goNativeKernel), block, C.size_t(blockSize), C.cl_uint(len(memObjects)), memList, memLocs, C.cl_uint(len(eventWaitList)), eventListPtr(eventWaitList), &event)
+	if err != C.CL_SUCCESS {
+		unregisterCallback(id)
+		return nil, toError(err)
+	}
+	ev := newEvent(event)
+	// The callback unregisters itself when it runs. If the command
+	// terminates without running (e.g. an event in the wait list failed)
+	// it's unregistered once the event completes.
+	if err := ev.OnStatus(CommmandExecStatusComplete, func(e *Event, err error) {
+		e.Release()
+		unregisterCallback(id)
+	}); err != nil {
+		unregisterCallback(id)
+		ev.Release()
+		return nil, err
+	}
+	return q.profile(ev, nil, CommandTypeNativeKernel, "", len(args))
+}
+
+//export goNativeKernel
+func goNativeKernel(block unsafe.Pointer) {
+	call, ok := unregisterCallback(*(*uintptr)(block)).(*nativeKernelCall)
+	if !ok {
+		return
+	}
+	call.fn(hostBytes(unsafe.Pointer(uintptr(block)+unsafe.Sizeof(uintptr(0))), call.size))
+}
@@ -0,0 +1,128 @@
+package cl
+
+import "fmt"
+
+// NDRange describes the index space of a kernel launch.
+type NDRange struct {
+	// Offset of the global IDs in each dimension or nil for zero.
+	Offset []int
+	// Global is the number of work-items in each dimension (1 to 3
+	// dimensions).
+	Global []int
+	// Local is the number of work-items in a work-group in each dimension
+	// or nil to let the implementation choose.
+	Local []int
+	// NonUniform allows Global not to be a multiple of Local. It requires
+	// an OpenCL 2.0 device and a program built with -cl-std=CL2.0 or later
+	// and without -cl-uniform-work-group-size (OpenCL 3.0 devices may not
+	// support it at all). It's up to the caller to ensure the latter.
+	NonUniform bool
+}
+
+// ErrInvalidNDRange is returned when an NDRange doesn't fit the limits of
+// the device or kernel.
+type ErrInvalidNDRange struct {
+	// Limit names the violated limit, e.g. "MaxWorkGroupSize" or
+	// "Dimensions" if Offset, Global and Local don't match.
+	Limit  string
+	Reason string
+}
+
+func (e ErrInvalidNDRange) Error() string {
+	return "cl: invalid NDRange: " + e.Reason
+}
+
+// RoundUp returns a copy of r with Global rounded up in each dimension to
+// a multiple of Local, and the number of work-items added as padding.
+// Kernels launched with the rounded range must skip the extra work-items
+// (e.g. by passing the original size as an argument). If Local is nil r
+// is returned unchanged.
+func (r NDRange) RoundUp() (NDRange, int) {
+	if r.Local == nil || len(r.Local) != len(r.Global) {
+		return r, 0
+	}
+	global := make([]int, len(r.Global))
+	total, padded := 1, 1
+	for i, g := range r.Global {
+		global[i] = g
+		if l := r.Local[i]; l > 0 && g%l != 0 {
+			global[i] += l - g%l
+		}
+		total *= g
+		padded *= global[i]
+	}
+	r.Global = global
+	return r, padded - total
+}
+
+// Validate checks r against the MaxWorkItemDimensions, MaxWorkItemSizes
+// and MaxWorkGroupSize of device. Unless NonUniform is set and the device
+// supports OpenCL 2.0, the global size must also be a multiple of the
+// local size (see RoundUp).
+func (r NDRange) Validate(device *Device) error {
+	return r.validate(device.MaxWorkItemDimensions(), device.MaxWorkItemSizes(), device.MaxWorkGroupSize(), device.versionAtLeast(2, 0))
+}
+
+func (r NDRange) validate(maxDims int, maxItemSizes []int, maxGroupSize int, nonUniformSupported bool) error {
+	uniform := !r.NonUniform || !nonUniformSupported
+	dims := len(r.Global)
+	if dims == 0 {
+		return ErrInvalidNDRange{Limit: "Dimensions", Reason: "global size has no dimensions"}
+	}
+	if dims > maxDims {
+		return ErrInvalidNDRange{Limit: "MaxWorkItemDimensions", Reason: fmt.Sprintf("%d dimensions exceed the device's MaxWorkItemDimensions of %d", dims, maxDims)}
+	}
+	if r.Offset != nil && len(r.Offset) != dims {
+		return ErrInvalidNDRange{Limit: "Dimensions", Reason: fmt.Sprintf("offset has %d dimensions but global size has %d", len(r.Offset), dims)}
+	}
+	if r.Local != nil && len(r.Local) != dims {
+		return ErrInvalidNDRange{Limit: "Dimensions", Reason: fmt.Sprintf("local size has %d dimensions but global size has %d", len(r.Local), dims)}
+	}
+	for i, g := range r.Global {
+		if g <= 0 {
+			return ErrInvalidNDRange{Limit: "Dimensions", Reason: fmt.Sprintf("global size %d in dimension %d is not positive", g, i)}
+		}
+	}
+	if r.Local == nil {
+		return nil
+	}
+	groupSize := 1
+	for i, l := range r.Local {
+		if l <= 0 {
+			return ErrInvalidNDRange{Limit: "Dimensions", Reason: fmt.Sprintf("local size %d in dimension %d is not positive", l, i)}
+		}
+		if i < len(maxItemSizes) && l > maxItemSizes[i] {
+			return ErrInvalidNDRange{Limit: "MaxWorkItemSizes", Reason: fmt.Sprintf("local size %d in dimension %d exceeds the device's MaxWorkItemSizes[%d] of %d", l, i, i, maxItemSizes[i])}
+		}
+		if uniform && r.Global[i]%l != 0 {
+			return ErrInvalidNDRange{Limit: "Uniform", Reason: fmt.Sprintf("global size %d in dimension %d is not a multiple of the local size %d", r.Global[i], i, l)}
+		}
+		groupSize *= l
+	}
+	if groupSize > maxGroupSize {
+		return ErrInvalidNDRange{Limit: "MaxWorkGroupSize", Reason: fmt.Sprintf("work-group size %v (%d work-items) exceeds the device's MaxWorkGroupSize of %d", r.Local, groupSize, maxGroupSize)}
+	}
+	return nil
+}
+
+// EnqueueNDRange validates r against the device of the queue and the
+// work-group size limit of the kernel, then enqueues the kernel.
+func (q *CommandQueue) EnqueueNDRange(kernel *Kernel, r NDRange, eventWaitList []*Event) (*Event, error) {
+	if err := r.Validate(q.device); err != nil {
+		return nil, err
+	}
+	if r.Local != nil {
+		maxSize, err := kernel.WorkGroupSize(q.device)
+		if err != nil {
+			return nil, err
+		}
+		groupSize := 1
+		for _, l := range r.Local {
+			groupSize *= l
+		}
+		if groupSize > maxSize {
+			return nil, ErrInvalidNDRange{Limit: "KernelWorkGroupSize", Reason: fmt.Sprintf("work-group size %v (%d work-items) exceeds the WorkGroupSize of kernel %s of %d", r.Local, groupSize, kernel.name, maxSize)}
+		}
+	}
+	return q.EnqueueNDRangeKernel(kernel, r.Offset, r.Global, r.Local, eventWaitList)
+}
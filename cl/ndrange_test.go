@@ -0,0 +1,135 @@
+package cl
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNDRangeRoundUp(t *testing.T) {
+	r, padding := NDRange{Global: []int{100, 30}, Local: []int{32, 8}}.RoundUp()
+	if !reflect.DeepEqual(r.Global, []int{128, 32}) || padding != 128*32-100*30 {
+		t.Errorf("expected [128 32] with padding %d, got %v with padding %d", 128*32-100*30, r.Global, padding)
+	}
+	r, padding = NDRange{Global: []int{100}}.RoundUp()
+	if !reflect.DeepEqual(r.Global, []int{100}) || padding != 0 {
+		t.Errorf("expected unchanged range, got %v with padding %d", r.Global, padding)
+	}
+}
+
+func TestNDRangeValidate(t *testing.T) {
+	cases := []struct {
+		r          NDRange
+		nonUniform bool // device supports OpenCL 2.0
+		limit      string
+	}{
+		{NDRange{Global: []int{128, 128}, Local: []int{16, 16}}, false, ""},
+		{NDRange{Global: []int{100}}, false, ""},
+		{NDRange{}, false, "Dimensions"},
+		{NDRange{Global: []int{1, 1, 1, 1}}, false, "MaxWorkItemDimensions"},
+		{NDRange{Global: []int{128, 128}, Local: []int{16}}, false, "Dimensions"},
+		{NDRange{Offset: []int{0}, Global: []int{128, 128}}, false, "Dimensions"},
+		{NDRange{Global: []int{128, 128}, Local: []int{1, 128}}, false, "MaxWorkItemSizes"},
+		{NDRange{Global: []int{128, 128}, Local: []int{64, 64}}, false, "MaxWorkGroupSize"},
+		{NDRange{Global: []int{100}, Local: []int{32}}, false, "Uniform"},
+		{NDRange{Global: []int{100}, Local: []int{32}}, true, "Uniform"},
+		{NDRange{Global: []int{100}, Local: []int{32}, NonUniform: true}, false, "Uniform"},
+		{NDRange{Global: []int{100}, Local: []int{32}, NonUniform: true}, true, ""},
+	}
+	for _, c := range cases {
+		err := c.r.validate(3, []int{256, 64, 64}, 256, c.nonUniform)
+		if c.limit == "" {
+			if err != nil {
+				t.Errorf("%+v: unexpected error %s", c.r, err)
+			}
+		} else if e, ok := err.(ErrInvalidNDRange); !ok || e.Limit != c.limit {
+			t.Errorf("%+v: expected %s error, got %v", c.r, c.limit, err)
+		}
+	}
+}
+
+func TestEnqueueNDRange(t *testing.T) {
+	var data [1000]float32
+	for i := range data {
+		data[i] = float32(i)
+	}
+
+	platforms, err := GetPlatforms()
+	if err != nil {
+		t.Fatalf("Failed to get platforms: %+v", err)
+	}
+	devices, err := platforms[0].GetDevices(DeviceTypeAll)
+	if err != nil {
+		t.Fatalf("Failed to get devices: %+v", err)
+	}
+	if len(devices) == 0 {
+		t.Fatalf("GetDevices returned no devices")
+	}
+	device := devices[0]
+	context, err := CreateContext([]*Device{device})
+	if err != nil {
+		t.Fatalf("CreateContext failed: %+v", err)
+	}
+	defer context.Release()
+	queue, err := context.CreateCommandQueue(device, 0)
+	if err != nil {
+		t.Fatalf("CreateCommandQueue failed: %+v", err)
+	}
+	defer queue.Release()
+	program, err := context.CreateProgramWithSource([]string{kernelSource})
+	if err != nil {
+		t.Fatalf("CreateProgramWithSource failed: %+v", err)
+	}
+	defer program.Release()
+	if err := program.BuildProgram(nil, ""); err != nil {
+		t.Fatalf("BuildProgram failed: %+v", err)
+	}
+	kernel, err := program.CreateKernel("square")
+	if err != nil {
+		t.Fatalf("CreateKernel failed: %+v", err)
+	}
+	defer kernel.Release()
+	input, err := context.CreateEmptyBuffer(MemReadOnly, 4*len(data))
+	if err != nil {
+		t.Fatalf("CreateBuffer failed for input: %+v", err)
+	}
+	defer input.Release()
+	output, err := context.CreateEmptyBuffer(MemWriteOnly, 4*len(data))
+	if err != nil {
+		t.Fatalf("CreateBuffer failed for output: %+v", err)
+	}
+	defer output.Release()
+	if _, err := queue.EnqueueWriteBufferFloat32(input, true, 0, data[:], nil); err != nil {
+		t.Fatalf("EnqueueWriteBufferFloat32 failed: %+v", err)
+	}
+	if err := kernel.SetArgs(input, output, uint32(len(data))); err != nil {
+		t.Fatalf("SetArgs failed: %+v", err)
+	}
+
+	invalid := NDRange{Global: []int{len(data)}, Local: []int{1, 1}}
+	if _, err := queue.EnqueueNDRange(kernel, invalid, nil); err == nil {
+		t.Errorf("EnqueueNDRange should reject %+v", invalid)
+	} else if _, ok := err.(ErrInvalidNDRange); !ok {
+		t.Errorf("Expected ErrInvalidNDRange for %+v, got %v", invalid, err)
+	}
+
+	local, err := kernel.WorkGroupSize(device)
+	if err != nil {
+		t.Fatalf("WorkGroupSize failed: %+v", err)
+	}
+	if max := device.MaxWorkItemSizes()[0]; local > max {
+		local = max
+	}
+	r, _ := NDRange{Global: []int{len(data)}, Local: []int{local}}.RoundUp()
+	if _, err := queue.EnqueueNDRange(kernel, r, nil); err != nil {
+		t.Fatalf("EnqueueNDRange failed: %+v", err)
+	}
+	results := make([]float32, len(data))
+	if _, err := queue.EnqueueReadBufferFloat32(output, true, 0, results, nil); err != nil {
+		t.Fatalf("EnqueueReadBufferFloat32 failed: %+v", err)
+	}
+	for i, v := range data {
+		if results[i] != v*v {
+			t.Fatalf("Result %d is %f, expected %f", i, results[i], v*v)
+		}
+	}
+}
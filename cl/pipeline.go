@@ -0,0 +1,249 @@
+package cl
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Pipeline streams a sequence of host chunks through a kernel, overlapping
+// the upload of chunk N+1, the kernel on chunk N and the download of chunk
+// N-1 on separate command queues. Every chunk in flight has its own device
+// buffers and pinned (MemAllocHostPtr) staging buffers that stay mapped
+// while the pipeline runs, so the source and sink work on host memory the
+// device can transfer from directly.
+type Pipeline struct {
+	// Kernel is enqueued once per chunk.
+	Kernel *Kernel
+	// Depth is the number of chunks in flight: 2 for double buffering or
+	// 3 for triple buffering. Defaults to 3.
+	Depth int
+	// ChunkSize is the size in bytes of the input buffers.
+	ChunkSize int
+	// OutputSize is the size in bytes of the output buffers. Defaults to
+	// ChunkSize.
+	OutputSize int
+
+	// Source fills chunk with the next input and returns the number of
+	// bytes written. It returns io.EOF (possibly with a final chunk) at the
+	// end of the input.
+	Source func(chunk []byte) (int, error)
+	// Setup sets the arguments of the kernel to process n bytes from in to
+	// out, and returns the range to enqueue it over and the number of bytes
+	// of output to download.
+	Setup func(kernel *Kernel, in, out *MemObject, n int) (NDRange, int, error)
+	// Sink consumes the output of the chunk with the given index. Chunks
+	// are passed in order and data is only valid during the call.
+	Sink func(index int, data []byte) error
+}
+
+// PipelineStats reports the work done by Pipeline.Run.
+type PipelineStats struct {
+	Chunks   int
+	BytesIn  int64
+	BytesOut int64
+	Elapsed  time.Duration
+}
+
+// InputThroughput returns the achieved input rate in bytes per second.
+func (s PipelineStats) InputThroughput() float64 {
+	if s.Elapsed <= 0 {
+		return 0
+	}
+	return float64(s.BytesIn) / s.Elapsed.Seconds()
+}
+
+// OutputThroughput returns the achieved output rate in bytes per second.
+func (s PipelineStats) OutputThroughput() float64 {
+	if s.Elapsed <= 0 {
+		return 0
+	}
+	return float64(s.BytesOut) / s.Elapsed.Seconds()
+}
+
+func (s PipelineStats) String() string {
+	return fmt.Sprintf("%d chunks, %d bytes in (%.1f MB/s), %d bytes out (%.1f MB/s) in %s",
+		s.Chunks, s.BytesIn, s.InputThroughput()/1e6, s.BytesOut, s.OutputThroughput()/1e6, s.Elapsed)
+}
+
+// pipelineSlot holds the buffers and the commands of a chunk in flight.
+type pipelineSlot struct {
+	in, out           *MemObject
+	stageIn, stageOut *MemObject
+	mapIn, mapOut     *MappedMemObject
+	download          *Event
+	index             int
+	outSize           int
+}
+
+// Run streams the input from Source through the kernel to Sink on device,
+// which must belong to ctx, until Source returns io.EOF or an error occurs.
+func (p *Pipeline) Run(ctx *Context, device *Device) (PipelineStats, error) {
+	var stats PipelineStats
+	if p.Kernel == nil || p.Source == nil || p.Setup == nil || p.Sink == nil {
+		return stats, errors.New("cl: Pipeline requires Kernel, Source, Setup and Sink")
+	}
+	if p.ChunkSize <= 0 {
+		return stats, errors.New("cl: Pipeline ChunkSize must be positive")
+	}
+	depth := p.Depth
+	if depth <= 0 {
+		depth = 3
+	}
+	outputSize := p.OutputSize
+	if outputSize <= 0 {
+		outputSize = p.ChunkSize
+	}
+
+	var queues [3]*CommandQueue
+	for i := range queues {
+		q, err := ctx.CreateCommandQueue(device, 0)
+		if err != nil {
+			for _, q := range queues[:i] {
+				q.Release()
+			}
+			return stats, err
+		}
+		queues[i] = q
+	}
+	upload, compute, download := queues[0], queues[1], queues[2]
+	slots := make([]*pipelineSlot, depth)
+	defer func() {
+		for _, q := range queues {
+			q.Finish()
+		}
+		for _, s := range slots {
+			if s != nil {
+				s.release(upload)
+			}
+		}
+		upload.Finish()
+		for _, q := range queues {
+			q.Release()
+		}
+	}()
+	for i := range slots {
+		s, err := newPipelineSlot(ctx, upload, p.ChunkSize, outputSize)
+		slots[i] = s
+		if err != nil {
+			return stats, err
+		}
+	}
+
+	start := time.Now()
+	index := 0
+	for eof := false; !eof; {
+		s := slots[index%depth]
+		// The slot is free once the download of the chunk that used it
+		// has completed and been consumed.
+		if err := p.drain(s, &stats); err != nil {
+			return stats, err
+		}
+		n, err := p.Source(s.mapIn.ByteSlice())
+		if err == io.EOF {
+			eof = true
+		} else if err != nil {
+			return stats, err
+		}
+		if n > p.ChunkSize {
+			return stats, fmt.Errorf("cl: Pipeline Source returned %d bytes for a chunk of %d", n, p.ChunkSize)
+		}
+		if n <= 0 {
+			continue
+		}
+		uploaded, err := upload.EnqueueWriteBuffer(s.in, false, 0, n, s.mapIn.Ptr(), nil)
+		if err != nil {
+			return stats, err
+		}
+		r, outSize, err := p.Setup(p.Kernel, s.in, s.out, n)
+		if err != nil {
+			return stats, err
+		}
+		if outSize < 0 || outSize > outputSize {
+			return stats, fmt.Errorf("cl: Pipeline Setup returned %d bytes of output for buffers of %d", outSize, outputSize)
+		}
+		computed, err := compute.EnqueueNDRange(p.Kernel, r, []*Event{uploaded})
+		if err != nil {
+			return stats, err
+		}
+		s.download, err = download.EnqueueReadBuffer(s.out, false, 0, outSize, s.mapOut.Ptr(), []*Event{computed})
+		if err != nil {
+			return stats, err
+		}
+		for _, q := range queues {
+			if err := q.Flush(); err != nil {
+				return stats, err
+			}
+		}
+		s.index = index
+		s.outSize = outSize
+		stats.Chunks++
+		stats.BytesIn += int64(n)
+		index++
+	}
+	for i := 0; i < depth; i++ {
+		if err := p.drain(slots[(index+i)%depth], &stats); err != nil {
+			return stats, err
+		}
+	}
+	stats.Elapsed = time.Since(start)
+	return stats, nil
+}
+
+// drain waits for the download of the chunk in s, if any, and passes it to
+// the sink.
+func (p *Pipeline) drain(s *pipelineSlot, stats *PipelineStats) error {
+	if s.download == nil {
+		return nil
+	}
+	err := s.download.Wait()
+	s.download = nil
+	if err != nil {
+		return err
+	}
+	stats.BytesOut += int64(s.outSize)
+	return p.Sink(s.index, s.mapOut.ByteSlice()[:s.outSize])
+}
+
+// newPipelineSlot allocates the buffers of a slot and maps its staging
+// buffers. On error the partially initialized slot is returned so that it
+// can be released.
+func newPipelineSlot(ctx *Context, queue *CommandQueue, inputSize, outputSize int) (*pipelineSlot, error) {
+	s := &pipelineSlot{}
+	var err error
+	if s.in, err = ctx.CreateEmptyBuffer(MemReadOnly, inputSize); err != nil {
+		return s, err
+	}
+	if s.out, err = ctx.CreateEmptyBuffer(MemWriteOnly, outputSize); err != nil {
+		return s, err
+	}
+	if s.stageIn, err = ctx.CreateEmptyBuffer(MemReadWrite|MemAllocHostPtr, inputSize); err != nil {
+		return s, err
+	}
+	if s.stageOut, err = ctx.CreateEmptyBuffer(MemReadWrite|MemAllocHostPtr, outputSize); err != nil {
+		return s, err
+	}
+	if s.mapIn, _, err = queue.EnqueueMapBuffer(s.stageIn, true, MapFlagWrite, 0, inputSize, nil); err != nil {
+		return s, err
+	}
+	if s.mapOut, _, err = queue.EnqueueMapBuffer(s.stageOut, true, MapFlagRead|MapFlagWrite, 0, outputSize, nil); err != nil {
+		return s, err
+	}
+	return s, nil
+}
+
+// release unmaps the staging buffers on queue and releases the buffers.
+func (s *pipelineSlot) release(queue *CommandQueue) {
+	if s.mapIn != nil {
+		queue.EnqueueUnmapMemObject(s.stageIn, s.mapIn, nil)
+	}
+	if s.mapOut != nil {
+		queue.EnqueueUnmapMemObject(s.stageOut, s.mapOut, nil)
+	}
+	for _, b := range []*MemObject{s.in, s.out, s.stageIn, s.stageOut} {
+		if b != nil {
+			b.Release()
+		}
+	}
+}
@@ -0,0 +1,16 @@
+// +build !cl10
+
+package cl
+
+// #include <stdlib.h>
+// #include "cl.h"
+import "C"
+import "unsafe"
+
+// extensionFunctionAddress returns the address of the extension function
+// named name for the platform, or nil if the platform does not provide it.
+func (p *Platform) extensionFunctionAddress(name string) unsafe.Pointer {
+	cName := C.CString(name)
+	defer C.free(unsafe.Pointer(cName))
+	return C.clGetExtensionFunctionAddressForPlatform(p.id, cName)
+}
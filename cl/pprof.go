@@ -0,0 +1,326 @@
+package cl
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"time"
+)
+
+// Field numbers of the messages in profile.proto
+// (https://github.com/google/pprof/blob/main/proto/profile.proto).
+const (
+	profileSampleType    = 1
+	profileSample        = 2
+	profileMapping       = 3
+	profileLocation      = 4
+	profileFunction      = 5
+	profileStringTable   = 6
+	profileTimeNanos     = 9
+	profileDurationNanos = 10
+	profilePeriodType    = 11
+	profilePeriod        = 12
+
+	valueTypeType = 1
+	valueTypeUnit = 2
+
+	sampleLocationID = 1
+	sampleValue      = 2
+	sampleLabel      = 3
+
+	labelKey = 1
+	labelStr = 2
+
+	mappingID              = 1
+	mappingMemoryStart     = 2
+	mappingMemoryLimit     = 3
+	mappingFilename        = 5
+	mappingHasFunctions    = 7
+	mappingHasFilenames    = 8
+	mappingHasLineNumbers  = 9
+	mappingHasInlineFrames = 10
+
+	locationID        = 1
+	locationMappingID = 2
+	locationAddress   = 3
+	locationLine      = 4
+
+	lineFunctionID = 1
+	lineLine       = 2
+
+	functionID         = 1
+	functionName       = 2
+	functionSystemName = 3
+	functionFilename   = 4
+	functionStartLine  = 5
+)
+
+// protoBuffer is a minimal protocol buffer encoder.
+type protoBuffer struct {
+	data []byte
+}
+
+func (b *protoBuffer) varint(x uint64) {
+	for x >= 0x80 {
+		b.data = append(b.data, byte(x)|0x80)
+		x >>= 7
+	}
+	b.data = append(b.data, byte(x))
+}
+
+func (b *protoBuffer) key(field, wireType int) {
+	b.varint(uint64(field)<<3 | uint64(wireType))
+}
+
+func (b *protoBuffer) uint64(field int, x uint64) {
+	if x != 0 {
+		b.key(field, 0)
+		b.varint(x)
+	}
+}
+
+func (b *protoBuffer) int64(field int, x int64) {
+	b.uint64(field, uint64(x))
+}
+
+func (b *protoBuffer) bool(field int, x bool) {
+	if x {
+		b.uint64(field, 1)
+	}
+}
+
+func (b *protoBuffer) bytes(field int, data []byte) {
+	b.key(field, 2)
+	b.varint(uint64(len(data)))
+	b.data = append(b.data, data...)
+}
+
+func (b *protoBuffer) packedUint64(field int, xs []uint64) {
+	var packed protoBuffer
+	for _, x := range xs {
+		packed.varint(x)
+	}
+	b.bytes(field, packed.data)
+}
+
+func (b *protoBuffer) message(field int, encode func(m *protoBuffer)) {
+	var m protoBuffer
+	encode(&m)
+	b.bytes(field, m.data)
+}
+
+// pprofBuilder collects the strings, functions and locations of a profile.
+type pprofBuilder struct {
+	profile   protoBuffer
+	strings   map[string]int64
+	functions map[string]uint64 // by name and file
+	locations map[uintptr]uint64
+	kernels   map[string]uint64 // locations for the command names
+	numFuncs  uint64
+	numLocs   uint64
+	minPC     uintptr
+	maxPC     uintptr
+}
+
+func (b *pprofBuilder) string(s string) int64 {
+	if i, ok := b.strings[s]; ok {
+		return i
+	}
+	i := int64(len(b.strings))
+	b.strings[s] = i
+	return i
+}
+
+func (b *pprofBuilder) function(name, file string, startLine int) uint64 {
+	key := name + "\x00" + file
+	if id, ok := b.functions[key]; ok {
+		return id
+	}
+	b.numFuncs++
+	id := b.numFuncs
+	b.functions[key] = id
+	b.profile.message(profileFunction, func(m *protoBuffer) {
+		m.uint64(functionID, id)
+		m.int64(functionName, b.string(name))
+		m.int64(functionSystemName, b.string(name))
+		m.int64(functionFilename, b.string(file))
+		m.int64(functionStartLine, int64(startLine))
+	})
+	return id
+}
+
+// location returns the location for a return address in a stack from
+// runtime.Callers, including the frames inlined at it.
+func (b *pprofBuilder) location(pc uintptr) uint64 {
+	if id, ok := b.locations[pc]; ok {
+		return id
+	}
+	type line struct {
+		function uint64
+		line     int
+	}
+	var lines []line
+	frames := runtime.CallersFrames([]uintptr{pc})
+	for {
+		frame, more := frames.Next()
+		startLine := 0
+		if frame.Func != nil {
+			_, startLine = frame.Func.FileLine(frame.Func.Entry())
+		}
+		lines = append(lines, line{b.function(frame.Function, frame.File, startLine), frame.Line})
+		if !more {
+			break
+		}
+	}
+	if b.minPC == 0 || pc < b.minPC {
+		b.minPC = pc
+	}
+	if pc > b.maxPC {
+		b.maxPC = pc
+	}
+	b.numLocs++
+	id := b.numLocs
+	b.locations[pc] = id
+	b.profile.message(profileLocation, func(m *protoBuffer) {
+		m.uint64(locationID, id)
+		m.uint64(locationMappingID, 1)
+		m.uint64(locationAddress, uint64(pc))
+		for _, l := range lines {
+			m.message(locationLine, func(m *protoBuffer) {
+				m.uint64(lineFunctionID, l.function)
+				m.int64(lineLine, int64(l.line))
+			})
+		}
+	})
+	return id
+}
+
+// kernelLocation returns the location of a synthetic leaf frame for the
+// command name, so that device time is attributed to it.
+func (b *pprofBuilder) kernelLocation(name string) uint64 {
+	if id, ok := b.kernels[name]; ok {
+		return id
+	}
+	function := b.function(name, "", 0)
+	b.numLocs++
+	id := b.numLocs
+	b.kernels[name] = id
+	b.profile.message(profileLocation, func(m *protoBuffer) {
+		m.uint64(locationID, id)
+		m.message(locationLine, func(m *protoBuffer) {
+			m.uint64(lineFunctionID, function)
+		})
+	})
+	return id
+}
+
+// WritePprof writes the recorded commands as a gzipped pprof profile
+// (profile.proto) that can be analyzed with go tool pprof. Every command
+// is a sample with its count and execution time on the device, attributed
+// to the kernel name (or command type) called from the Go call stack that
+// enqueued it.
+func (p *Profiler) WritePprof(w io.Writer) error {
+	records := p.Records()
+	b := &pprofBuilder{
+		strings:   map[string]int64{"": 0},
+		functions: make(map[string]uint64),
+		locations: make(map[uintptr]uint64),
+		kernels:   make(map[string]uint64),
+	}
+
+	type sampleKey struct {
+		command string
+		stack   string
+	}
+	type sample struct {
+		locations []uint64
+		command   string
+		count     int64
+		time      int64
+	}
+	var samples []*sample
+	index := make(map[sampleKey]*sample)
+	first, last := int64(-1), int64(-1)
+	for _, r := range records {
+		if r.Err != nil {
+			continue
+		}
+		if first < 0 || r.Queued < first {
+			first = r.Queued
+		}
+		if r.End > last {
+			last = r.End
+		}
+		command := r.Name
+		if command == "" {
+			command = r.Type.String()
+		}
+		key := sampleKey{command, fmt.Sprint(r.stack)}
+		s := index[key]
+		if s == nil {
+			locations := []uint64{b.kernelLocation(command)}
+			for _, pc := range r.stack {
+				locations = append(locations, b.location(pc))
+			}
+			s = &sample{locations: locations, command: command}
+			index[key] = s
+			samples = append(samples, s)
+		}
+		s.count++
+		s.time += int64(r.ExecTime)
+	}
+
+	b.profile.message(profileSampleType, func(m *protoBuffer) {
+		m.int64(valueTypeType, b.string("count"))
+		m.int64(valueTypeUnit, b.string("count"))
+	})
+	b.profile.message(profileSampleType, func(m *protoBuffer) {
+		m.int64(valueTypeType, b.string("device_time"))
+		m.int64(valueTypeUnit, b.string("nanoseconds"))
+	})
+	for _, s := range samples {
+		b.profile.message(profileSample, func(m *protoBuffer) {
+			m.packedUint64(sampleLocationID, s.locations)
+			m.packedUint64(sampleValue, []uint64{uint64(s.count), uint64(s.time)})
+			m.message(sampleLabel, func(m *protoBuffer) {
+				m.int64(labelKey, b.string("command"))
+				m.int64(labelStr, b.string(s.command))
+			})
+		})
+	}
+	if b.numLocs > 0 {
+		executable, _ := os.Executable()
+		b.profile.message(profileMapping, func(m *protoBuffer) {
+			m.uint64(mappingID, 1)
+			m.uint64(mappingMemoryStart, uint64(b.minPC))
+			m.uint64(mappingMemoryLimit, uint64(b.maxPC)+1)
+			m.int64(mappingFilename, b.string(executable))
+			m.bool(mappingHasFunctions, true)
+			m.bool(mappingHasFilenames, true)
+			m.bool(mappingHasLineNumbers, true)
+			m.bool(mappingHasInlineFrames, true)
+		})
+	}
+	b.profile.int64(profileTimeNanos, time.Now().UnixNano())
+	b.profile.int64(profileDurationNanos, last-first)
+	b.profile.message(profilePeriodType, func(m *protoBuffer) {
+		m.int64(valueTypeType, b.string("device_time"))
+		m.int64(valueTypeUnit, b.string("nanoseconds"))
+	})
+	b.profile.int64(profilePeriod, 1)
+	stringTable := make([]string, len(b.strings))
+	for s, i := range b.strings {
+		stringTable[i] = s
+	}
+	for _, s := range stringTable {
+		b.profile.bytes(profileStringTable, []byte(s))
+	}
+
+	zw := gzip.NewWriter(w)
+	if _, err := zw.Write(b.profile.data); err != nil {
+		return err
+	}
+	return zw.Close()
+}
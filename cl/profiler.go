@@ -0,0 +1,287 @@
+package cl
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"runtime"
+	"sort"
+	"sync"
+	"text/tabwriter"
+	"time"
+)
+
+// Profiler records the commands enqueued on the command queues it's
+// attached to (see CommandQueue.SetProfiler) and collects their profiling
+// info when they complete. The queues must be created with
+// CommandQueueProfilingEnable. A Profiler can be shared by queues used from
+// several goroutines, and its records can be read while commands are
+// being enqueued.
+type Profiler struct {
+	mu      sync.Mutex
+	done    *sync.Cond // signalled when pending drops to zero
+	queues  []*CommandQueue
+	records []ProfileRecord
+	pending int // recorded commands that haven't completed
+	gen     int // incremented by Reset
+}
+
+// ProfileRecord is a command recorded by a Profiler.
+type ProfileRecord struct {
+	Queue int // index of the queue in the order it was attached
+	Type  CommandType
+	Name  string // the kernel name for kernel commands
+	Bytes int    // the size of transfers
+	EventProfile
+	// Err is set if the command terminated abnormally or its profiling
+	// info couldn't be retrieved.
+	Err error
+
+	stack []uintptr // Go call stack that enqueued the command
+}
+
+// Label returns a short description of the command, e.g. the kernel name
+// or the type and size of a transfer.
+func (r ProfileRecord) Label() string {
+	if r.Name != "" {
+		return r.Name
+	}
+	if r.Bytes > 0 {
+		return fmt.Sprintf("%s %d bytes", r.Type, r.Bytes)
+	}
+	return r.Type.String()
+}
+
+// NewProfiler returns an empty profiler.
+func NewProfiler() *Profiler {
+	return &Profiler{}
+}
+
+// SetProfiler attaches a profiler that records every command enqueued on
+// the queue from now on, or detaches it if p is nil. It must not be called
+// concurrently with enqueueing commands on the queue, but the profiler can
+// be used concurrently (see Profiler).
+func (q *CommandQueue) SetProfiler(p *Profiler) {
+	q.profiler = p
+	if p != nil {
+		p.mu.Lock()
+		p.queueIndex(q)
+		p.mu.Unlock()
+	}
+}
+
+// profile records the command of event with the queue's profiler, if any,
+// and passes event and err through.
+func (q *CommandQueue) profile(event *Event, err error, commandType CommandType, name string, bytes int) (*Event, error) {
+	if err == nil && q.profiler != nil {
+		q.profiler.record(q, event, commandType, name, bytes)
+	}
+	return event, err
+}
+
+func (p *Profiler) queueIndex(q *CommandQueue) int {
+	for i, pq := range p.queues {
+		if pq == q {
+			return i
+		}
+	}
+	p.queues = append(p.queues, q)
+	return len(p.queues) - 1
+}
+
+func (p *Profiler) record(q *CommandQueue, event *Event, commandType CommandType, name string, bytes int) {
+	// Skip runtime.Callers, record and CommandQueue.profile so the stack
+	// starts at the Enqueue method.
+	var pcs [64]uintptr
+	n := runtime.Callers(3, pcs[:])
+	p.mu.Lock()
+	r := ProfileRecord{Queue: p.queueIndex(q), Type: commandType, Name: name, Bytes: bytes, stack: append([]uintptr(nil), pcs[:n]...)}
+	gen := p.gen
+	p.pending++
+	p.mu.Unlock()
+	// Profile the reference passed to the callback rather than event,
+	// which the caller may release right after enqueueing the command.
+	err := event.OnStatus(CommmandExecStatusComplete, func(live *Event, err error) {
+		if err == nil {
+			r.EventProfile, err = live.Profile()
+		}
+		live.Release()
+		r.Err = err
+		p.add(r, gen)
+	})
+	if err != nil {
+		r.Err = err
+		p.add(r, gen)
+	}
+}
+
+// add stores a completed record unless the profiler was reset after the
+// command was enqueued.
+func (p *Profiler) add(r ProfileRecord, gen int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if gen == p.gen {
+		p.records = append(p.records, r)
+	}
+	p.pending--
+	if p.pending == 0 && p.done != nil {
+		p.done.Broadcast()
+	}
+}
+
+// Records waits until none of the recorded commands is pending and returns
+// them ordered by start time. Call it after the queues have been finished.
+// While other goroutines keep enqueueing commands it may wait for those as
+// well, and it blocks forever if a command never completes (e.g. it waits
+// for a user event that is never set). Use Completed to get the records
+// without waiting.
+func (p *Profiler) Records() []ProfileRecord {
+	p.mu.Lock()
+	for p.pending > 0 {
+		if p.done == nil {
+			p.done = sync.NewCond(&p.mu)
+		}
+		p.done.Wait()
+	}
+	p.mu.Unlock()
+	return p.Completed()
+}
+
+// Completed returns the recorded commands that have completed so far
+// ordered by start time, without waiting for the pending ones.
+func (p *Profiler) Completed() []ProfileRecord {
+	p.mu.Lock()
+	records := append([]ProfileRecord(nil), p.records...)
+	p.mu.Unlock()
+	sort.SliceStable(records, func(i, j int) bool { return records[i].Start < records[j].Start })
+	return records
+}
+
+// Reset discards the recorded commands, including those that are still
+// pending. It doesn't wait for them to complete.
+func (p *Profiler) Reset() {
+	p.mu.Lock()
+	p.records = nil
+	p.gen++
+	p.mu.Unlock()
+}
+
+type chromeTraceEvent struct {
+	Name  string                 `json:"name"`
+	Cat   string                 `json:"cat,omitempty"`
+	Ph    string                 `json:"ph"`
+	Ts    float64                `json:"ts"`
+	Dur   float64                `json:"dur,omitempty"`
+	Pid   int                    `json:"pid"`
+	Tid   int                    `json:"tid"`
+	Args  map[string]interface{} `json:"args,omitempty"`
+	Scope string                 `json:"s,omitempty"`
+}
+
+// WriteChromeTrace writes the recorded commands in the Chrome trace event
+// format which can be loaded in chrome://tracing or Perfetto. Every queue
+// is shown as a thread and times are relative to the first command.
+func (p *Profiler) WriteChromeTrace(w io.Writer) error {
+	records := p.Records()
+	p.mu.Lock()
+	queues := append([]*CommandQueue(nil), p.queues...)
+	p.mu.Unlock()
+
+	origin := int64(-1)
+	for _, r := range records {
+		if r.Err == nil && (origin < 0 || r.Queued < origin) {
+			origin = r.Queued
+		}
+	}
+	events := make([]chromeTraceEvent, 0, len(records)+len(queues))
+	for i, q := range queues {
+		name := fmt.Sprintf("Queue %d", i)
+		if q.device != nil {
+			name += " (" + q.device.Name() + ")"
+		}
+		events = append(events, chromeTraceEvent{Name: "thread_name", Ph: "M", Tid: i, Args: map[string]interface{}{"name": name}})
+	}
+	for _, r := range records {
+		if r.Err != nil {
+			events = append(events, chromeTraceEvent{Name: r.Label(), Cat: r.Type.String(), Ph: "i", Scope: "t", Tid: r.Queue, Args: map[string]interface{}{"error": r.Err.Error()}})
+			continue
+		}
+		args := map[string]interface{}{
+			"queue_us":  float64(r.QueueTime) / 1e3,
+			"submit_us": float64(r.SubmitTime) / 1e3,
+		}
+		if r.Bytes > 0 {
+			args["bytes"] = r.Bytes
+		}
+		events = append(events, chromeTraceEvent{
+			Name: r.Label(),
+			Cat:  r.Type.String(),
+			Ph:   "X",
+			Ts:   float64(r.Start-origin) / 1e3,
+			Dur:  float64(r.ExecTime) / 1e3,
+			Tid:  r.Queue,
+			Args: args,
+		})
+	}
+	return json.NewEncoder(w).Encode(struct {
+		TraceEvents     []chromeTraceEvent `json:"traceEvents"`
+		DisplayTimeUnit string             `json:"displayTimeUnit"`
+	}{events, "ns"})
+}
+
+// ProfileSummary aggregates the execution time of the recorded commands
+// with the same kernel name or, for other commands, the same type.
+type ProfileSummary struct {
+	Name   string
+	Count  int
+	Errors int
+	Total  time.Duration
+	Mean   time.Duration
+	Max    time.Duration
+}
+
+// Summary returns the execution time of the recorded commands grouped by
+// kernel name (or command type for other commands), ordered by total time.
+func (p *Profiler) Summary() []ProfileSummary {
+	index := make(map[string]int)
+	var summary []ProfileSummary
+	for _, r := range p.Records() {
+		name := r.Name
+		if name == "" {
+			name = r.Type.String()
+		}
+		i, ok := index[name]
+		if !ok {
+			i = len(summary)
+			index[name] = i
+			summary = append(summary, ProfileSummary{Name: name})
+		}
+		s := &summary[i]
+		if r.Err != nil {
+			s.Errors++
+			continue
+		}
+		s.Count++
+		s.Total += r.ExecTime
+		if r.ExecTime > s.Max {
+			s.Max = r.ExecTime
+		}
+	}
+	for i := range summary {
+		if summary[i].Count > 0 {
+			summary[i].Mean = summary[i].Total / time.Duration(summary[i].Count)
+		}
+	}
+	sort.SliceStable(summary, func(i, j int) bool { return summary[i].Total > summary[j].Total })
+	return summary
+}
+
+// WriteSummary writes the Summary as a table.
+func (p *Profiler) WriteSummary(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "Command\tCount\tErrors\tTotal\tMean\tMax\t")
+	for _, s := range p.Summary() {
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%s\t%s\t%s\t\n", s.Name, s.Count, s.Errors, s.Total, s.Mean, s.Max)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,119 @@
+package cl
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"reflect"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func testProfiler() *Profiler {
+	p := NewProfiler()
+	p.records = []ProfileRecord{
+		{Type: CommandTypeNDRangeKernel, Name: "square", EventProfile: EventProfile{Queued: 1000, Submit: 1500, Start: 2000, End: 5000, ExecTime: 3000}},
+		{Type: CommandTypeWriteBuffer, Bytes: 4096, EventProfile: EventProfile{Queued: 0, Submit: 100, Start: 500, End: 1500, ExecTime: 1000}},
+		{Type: CommandTypeNDRangeKernel, Name: "square", EventProfile: EventProfile{Queued: 5000, Submit: 5100, Start: 6000, End: 7000, ExecTime: 1000}},
+		{Type: CommandTypeReadBuffer, Bytes: 4096, Err: errors.New("failed")},
+	}
+	return p
+}
+
+func TestProfilerSummary(t *testing.T) {
+	expected := []ProfileSummary{
+		{Name: "square", Count: 2, Total: 4 * time.Microsecond, Mean: 2 * time.Microsecond, Max: 3 * time.Microsecond},
+		{Name: "WriteBuffer", Count: 1, Total: time.Microsecond, Mean: time.Microsecond, Max: time.Microsecond},
+		{Name: "ReadBuffer", Errors: 1},
+	}
+	if summary := testProfiler().Summary(); !reflect.DeepEqual(summary, expected) {
+		t.Errorf("expected %+v got %+v", expected, summary)
+	}
+}
+
+func TestProfilerChromeTrace(t *testing.T) {
+	var buf bytes.Buffer
+	if err := testProfiler().WriteChromeTrace(&buf); err != nil {
+		t.Fatal(err)
+	}
+	var trace struct {
+		TraceEvents []struct {
+			Name string
+			Ph   string
+			Ts   float64
+			Dur  float64
+		}
+	}
+	if err := json.Unmarshal(buf.Bytes(), &trace); err != nil {
+		t.Fatalf("invalid trace JSON: %s", err)
+	}
+	if len(trace.TraceEvents) != 4 {
+		t.Fatalf("expected 4 trace events, got %d", len(trace.TraceEvents))
+	}
+	// Records without errors are ordered by start time and relative to the
+	// first queued command.
+	first := trace.TraceEvents[1]
+	if first.Name != "WriteBuffer 4096 bytes" || first.Ph != "X" || first.Ts != 0.5 || first.Dur != 1 {
+		t.Errorf("unexpected first event %+v", first)
+	}
+}
+
+func TestProfilerPprof(t *testing.T) {
+	p := testProfiler()
+	var pcs [16]uintptr
+	stack := pcs[:runtime.Callers(1, pcs[:])]
+	for i := range p.records {
+		p.records[i].stack = stack
+	}
+	var buf bytes.Buffer
+	if err := p.WritePprof(&buf); err != nil {
+		t.Fatal(err)
+	}
+	zr, err := gzip.NewReader(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := ioutil.ReadAll(zr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{"device_time", "square", "WriteBuffer", "TestProfilerPprof"} {
+		if !bytes.Contains(data, []byte(s)) {
+			t.Errorf("expected %q in the profile", s)
+		}
+	}
+}
+
+func TestProfilerPending(t *testing.T) {
+	p := NewProfiler()
+	p.mu.Lock()
+	p.pending = 2
+	p.mu.Unlock()
+	done := make(chan struct{})
+	go func() {
+		<-done
+		p.add(ProfileRecord{Name: "first"}, 0)
+		p.add(ProfileRecord{Name: "second"}, 0)
+	}()
+	if records := p.Completed(); len(records) != 0 {
+		t.Errorf("expected no completed records, got %+v", records)
+	}
+	close(done)
+	if records := p.Records(); len(records) != 2 {
+		t.Errorf("expected 2 records, got %+v", records)
+	}
+
+	// Records of commands pending during Reset are discarded.
+	p.mu.Lock()
+	p.pending = 1
+	gen := p.gen
+	p.mu.Unlock()
+	p.Reset()
+	p.add(ProfileRecord{Name: "stale"}, gen)
+	if records := p.Records(); len(records) != 0 {
+		t.Errorf("expected no records after Reset, got %+v", records)
+	}
+}
@@ -16,9 +16,18 @@ func (e BuildError) Error() string {
 	return fmt.Sprintf("cl: build error (%s)", string(e))
 }
 
+// These are set by the version specific files when the package is built
+// against an OpenCL version (or extension) that provides them.
+var (
+	createProgramWithIL              func(ctx *Context, il []byte) (C.cl_program, error)
+	createProgramWithILKHR           func(ctx *Context, il []byte) (C.cl_program, error)
+	setProgramSpecializationConstant func(p *Program, specId uint32, size int, value unsafe.Pointer) error
+)
+
 type Program struct {
 	clProgram C.cl_program
 	devices   []*Device
+	sourceMap *SourceMap
 }
 
 func releaseProgram(p *Program) {
@@ -28,10 +37,45 @@ func releaseProgram(p *Program) {
 	}
 }
 
+// retainProgram returns a Program for an existing cl_program (e.g. as
+// returned by an info query), retaining it for the lifetime of the Program.
+func retainProgram(clProgram C.cl_program) (*Program, error) {
+	if clProgram == nil {
+		return nil, ErrUnknown
+	}
+	var numDevices C.cl_uint
+	if err := C.clGetProgramInfo(clProgram, C.CL_PROGRAM_NUM_DEVICES, C.size_t(unsafe.Sizeof(numDevices)), unsafe.Pointer(&numDevices), nil); err != C.CL_SUCCESS {
+		return nil, toError(err)
+	}
+	var devices []*Device
+	if numDevices > 0 {
+		deviceIds := make([]C.cl_device_id, numDevices)
+		if err := C.clGetProgramInfo(clProgram, C.CL_PROGRAM_DEVICES, C.size_t(unsafe.Sizeof(deviceIds[0]))*C.size_t(numDevices), unsafe.Pointer(&deviceIds[0]), nil); err != C.CL_SUCCESS {
+			return nil, toError(err)
+		}
+		devices = make([]*Device, numDevices)
+		for i, id := range deviceIds {
+			devices[i] = &Device{id: id}
+		}
+	}
+	if err := C.clRetainProgram(clProgram); err != C.CL_SUCCESS {
+		return nil, toError(err)
+	}
+	program := &Program{clProgram: clProgram, devices: devices}
+	runtime.SetFinalizer(program, releaseProgram)
+	return program, nil
+}
+
 func (p *Program) Release() {
 	releaseProgram(p)
 }
 
+// SourceMap returns the mapping from lines of the program source to the
+// original files for programs created with CreateProgramFromFS, or nil.
+func (p *Program) SourceMap() *SourceMap {
+	return p.sourceMap
+}
+
 func (p *Program) BuildProgram(devices []*Device, options string) error {
 	var cOptions *C.char
 	if options != "" {
@@ -61,11 +105,29 @@ func (p *Program) BuildProgram(devices []*Device, options string) error {
 		if err != C.CL_SUCCESS {
 			return toError(err)
 		}
-		return BuildError(string(buffer[:bLen]))
+		return BuildError(p.sourceMap.Rewrite(string(buffer[:bLen])))
 	}
 	return nil
 }
 
+// BuildProgramWithOptions validates options against each of the devices (or
+// all devices of the program if devices is empty) and builds the program.
+func (p *Program) BuildProgramWithOptions(devices []*Device, options *BuildOptions) error {
+	if options == nil {
+		return p.BuildProgram(devices, "")
+	}
+	validateDevices := devices
+	if len(validateDevices) == 0 {
+		validateDevices = p.devices
+	}
+	for _, d := range validateDevices {
+		if err := options.Validate(d); err != nil {
+			return err
+		}
+	}
+	return p.BuildProgram(devices, options.String())
+}
+
 func (p *Program) CreateKernel(name string) (*Kernel, error) {
 	cName := C.CString(name)
 	defer C.free(unsafe.Pointer(cName))
@@ -74,7 +136,55 @@ func (p *Program) CreateKernel(name string) (*Kernel, error) {
 	if err != C.CL_SUCCESS {
 		return nil, toError(err)
 	}
-	kernel := &Kernel{clKernel: clKernel, name: name}
+	kernel := &Kernel{clKernel: clKernel, name: name, program: p}
 	runtime.SetFinalizer(kernel, releaseKernel)
 	return kernel, nil
 }
+
+// SetSpecializationConstant sets the value of a specialization constant in
+// a program created from SPIR-V. It must be called before the program is
+// built. Supported value types are bool, int8, uint8, int16, uint16,
+// int32, uint32, int64, uint64, float32 and float64. Requires building with
+// the cl22 tag, otherwise ErrUnsupported is returned.
+func (p *Program) SetSpecializationConstant(specId uint32, value interface{}) error {
+	switch val := value.(type) {
+	case bool:
+		// SPIR-V OpSpecConstantTrue/False are set using a single byte
+		var b uint8
+		if val {
+			b = 1
+		}
+		return p.SetSpecializationConstantUnsafe(specId, 1, unsafe.Pointer(&b))
+	case int8:
+		return p.SetSpecializationConstantUnsafe(specId, int(unsafe.Sizeof(val)), unsafe.Pointer(&val))
+	case uint8:
+		return p.SetSpecializationConstantUnsafe(specId, int(unsafe.Sizeof(val)), unsafe.Pointer(&val))
+	case int16:
+		return p.SetSpecializationConstantUnsafe(specId, int(unsafe.Sizeof(val)), unsafe.Pointer(&val))
+	case uint16:
+		return p.SetSpecializationConstantUnsafe(specId, int(unsafe.Sizeof(val)), unsafe.Pointer(&val))
+	case int32:
+		return p.SetSpecializationConstantUnsafe(specId, int(unsafe.Sizeof(val)), unsafe.Pointer(&val))
+	case uint32:
+		return p.SetSpecializationConstantUnsafe(specId, int(unsafe.Sizeof(val)), unsafe.Pointer(&val))
+	case int64:
+		return p.SetSpecializationConstantUnsafe(specId, int(unsafe.Sizeof(val)), unsafe.Pointer(&val))
+	case uint64:
+		return p.SetSpecializationConstantUnsafe(specId, int(unsafe.Sizeof(val)), unsafe.Pointer(&val))
+	case float32:
+		return p.SetSpecializationConstantUnsafe(specId, int(unsafe.Sizeof(val)), unsafe.Pointer(&val))
+	case float64:
+		return p.SetSpecializationConstantUnsafe(specId, int(unsafe.Sizeof(val)), unsafe.Pointer(&val))
+	default:
+		return fmt.Errorf("cl: unsupported specialization constant type for id %d: %T", specId, value)
+	}
+}
+
+// SetSpecializationConstantUnsafe sets the value of a specialization
+// constant from size bytes at value.
+func (p *Program) SetSpecializationConstantUnsafe(specId uint32, size int, value unsafe.Pointer) error {
+	if setProgramSpecializationConstant == nil {
+		return ErrUnsupported
+	}
+	return setProgramSpecializationConstant(p, specId, size, value)
+}
@@ -0,0 +1,41 @@
+// +build !cl10
+
+package cl
+
+// #include "cl.h"
+//
+// typedef cl_program (CL_API_CALL *createProgramWithILKHRFunc)(cl_context, const void *, size_t, cl_int *);
+//
+// static cl_program callCreateProgramWithILKHR(void *fn, cl_context context, const void *il, size_t length, cl_int *err) {
+// 	return ((createProgramWithILKHRFunc)fn)(context, il, length, err);
+// }
+import "C"
+
+import (
+	"strings"
+	"unsafe"
+)
+
+func init() {
+	createProgramWithILKHR = createProgramWithILFromExtension
+}
+
+// createProgramWithILFromExtension calls clCreateProgramWithILKHR from the
+// cl_khr_il_program extension.
+func createProgramWithILFromExtension(ctx *Context, il []byte) (C.cl_program, error) {
+	for _, d := range ctx.devices {
+		if !strings.Contains(" "+d.Extensions()+" ", " cl_khr_il_program ") {
+			return nil, ErrUnsupported
+		}
+	}
+	fn := ctx.devices[0].Platform().extensionFunctionAddress("clCreateProgramWithILKHR")
+	if fn == nil {
+		return nil, ErrUnsupported
+	}
+	var err C.cl_int
+	clProgram := C.callCreateProgramWithILKHR(fn, ctx.clContext, unsafe.Pointer(&il[0]), C.size_t(len(il)), &err)
+	if err != C.CL_SUCCESS {
+		return nil, toError(err)
+	}
+	return clProgram, nil
+}
@@ -0,0 +1,20 @@
+// +build cl21 cl22
+
+package cl
+
+// #include "cl.h"
+import "C"
+import "unsafe"
+
+func init() {
+	createProgramWithIL = createProgramWithILCore
+}
+
+func createProgramWithILCore(ctx *Context, il []byte) (C.cl_program, error) {
+	var err C.cl_int
+	clProgram := C.clCreateProgramWithIL(ctx.clContext, unsafe.Pointer(&il[0]), C.size_t(len(il)), &err)
+	if err != C.CL_SUCCESS {
+		return nil, toError(err)
+	}
+	return clProgram, nil
+}
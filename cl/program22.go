@@ -0,0 +1,13 @@
+// +build cl22
+
+package cl
+
+// #include "cl.h"
+import "C"
+import "unsafe"
+
+func init() {
+	setProgramSpecializationConstant = func(p *Program, specId uint32, size int, value unsafe.Pointer) error {
+		return toError(C.clSetProgramSpecializationConstant(p.clProgram, C.cl_uint(specId), C.size_t(size), value))
+	}
+}
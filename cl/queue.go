@@ -1,9 +1,33 @@
 package cl
 
 // #include "cl.h"
+//
+// #ifndef CL_QUEUE_ON_DEVICE
+// #define CL_QUEUE_ON_DEVICE         (1 << 2)
+// #define CL_QUEUE_ON_DEVICE_DEFAULT (1 << 3)
+// #define CL_QUEUE_PROPERTIES        0x1093
+// #define CL_QUEUE_SIZE              0x1094
+// #endif
+// #ifndef CL_QUEUE_DEVICE_DEFAULT
+// #define CL_QUEUE_DEVICE_DEFAULT    0x1095
+// #endif
+// #ifndef CL_QUEUE_PRIORITY_KHR
+// #define CL_QUEUE_PRIORITY_KHR      0x1096
+// #define CL_QUEUE_PRIORITY_HIGH_KHR (1 << 0)
+// #define CL_QUEUE_PRIORITY_MED_KHR  (1 << 1)
+// #define CL_QUEUE_PRIORITY_LOW_KHR  (1 << 2)
+// #endif
+// #ifndef CL_QUEUE_THROTTLE_KHR
+// #define CL_QUEUE_THROTTLE_KHR      0x1097
+// #define CL_QUEUE_THROTTLE_HIGH_KHR (1 << 0)
+// #define CL_QUEUE_THROTTLE_MED_KHR  (1 << 1)
+// #define CL_QUEUE_THROTTLE_LOW_KHR  (1 << 2)
+// #endif
 import "C"
 
 import (
+	"runtime"
+	"strings"
 	"unsafe"
 )
 
@@ -12,11 +36,102 @@ type CommandQueueProperty int
 const (
 	CommandQueueOutOfOrderExecModeEnable CommandQueueProperty = C.CL_QUEUE_OUT_OF_ORDER_EXEC_MODE_ENABLE
 	CommandQueueProfilingEnable          CommandQueueProperty = C.CL_QUEUE_PROFILING_ENABLE
+	// CommandQueueOnDevice creates a queue on the device for kernels
+	// enqueueing kernels. It requires CommandQueueOutOfOrderExecModeEnable.
+	// OpenCL 2.0.
+	CommandQueueOnDevice CommandQueueProperty = C.CL_QUEUE_ON_DEVICE
+	// CommandQueueOnDeviceDefault makes an on-device queue the default one
+	// of the device. OpenCL 2.0.
+	CommandQueueOnDeviceDefault CommandQueueProperty = C.CL_QUEUE_ON_DEVICE_DEFAULT
+)
+
+func (p CommandQueueProperty) String() string {
+	var parts []string
+	if p&CommandQueueOutOfOrderExecModeEnable != 0 {
+		parts = append(parts, "OutOfOrderExecModeEnable")
+	}
+	if p&CommandQueueProfilingEnable != 0 {
+		parts = append(parts, "ProfilingEnable")
+	}
+	if p&CommandQueueOnDevice != 0 {
+		parts = append(parts, "OnDevice")
+	}
+	if p&CommandQueueOnDeviceDefault != 0 {
+		parts = append(parts, "OnDeviceDefault")
+	}
+	if parts == nil {
+		parts = append(parts, "None")
+	}
+	return strings.Join(parts, "|")
+}
+
+// QueuePriority is a priority hint for a command queue
+// (cl_khr_priority_hints).
+type QueuePriority int
+
+const (
+	QueuePriorityDefault QueuePriority = 0
+	QueuePriorityHigh    QueuePriority = C.CL_QUEUE_PRIORITY_HIGH_KHR
+	QueuePriorityMedium  QueuePriority = C.CL_QUEUE_PRIORITY_MED_KHR
+	QueuePriorityLow     QueuePriority = C.CL_QUEUE_PRIORITY_LOW_KHR
+)
+
+// QueueThrottle is a hint for the power/performance trade-off of a command
+// queue (cl_khr_throttle_hints).
+type QueueThrottle int
+
+const (
+	QueueThrottleDefault QueueThrottle = 0
+	QueueThrottleHigh    QueueThrottle = C.CL_QUEUE_THROTTLE_HIGH_KHR
+	QueueThrottleMedium  QueueThrottle = C.CL_QUEUE_THROTTLE_MED_KHR
+	QueueThrottleLow     QueueThrottle = C.CL_QUEUE_THROTTLE_LOW_KHR
+)
+
+// QueueProperties are the properties of a command queue created with
+// CreateCommandQueueWithProperties.
+type QueueProperties struct {
+	Properties CommandQueueProperty
+	// Size is the size in bytes of an on-device queue or 0 for the
+	// device's preferred size.
+	Size int
+	// Priority and Throttle are hints that are ignored if the device
+	// doesn't support the extension providing them.
+	Priority QueuePriority
+	Throttle QueueThrottle
+}
+
+// list returns the properties as a zero terminated list of
+// cl_queue_properties.
+func (p QueueProperties) list() []C.cl_ulong {
+	list := []C.cl_ulong{C.CL_QUEUE_PROPERTIES, C.cl_ulong(p.Properties)}
+	if p.Size != 0 {
+		list = append(list, C.CL_QUEUE_SIZE, C.cl_ulong(p.Size))
+	}
+	if p.Priority != QueuePriorityDefault {
+		list = append(list, C.CL_QUEUE_PRIORITY_KHR, C.cl_ulong(p.Priority))
+	}
+	if p.Throttle != QueueThrottleDefault {
+		list = append(list, C.CL_QUEUE_THROTTLE_KHR, C.cl_ulong(p.Throttle))
+	}
+	return append(list, 0)
+}
+
+// The version specific files set these when the package is built against
+// a version of OpenCL that has the function. properties is a zero
+// terminated list of cl_queue_properties.
+var (
+	// createCommandQueueWithProperties calls the OpenCL 2.0 function.
+	createCommandQueueWithProperties func(ctx *Context, device *Device, properties []C.cl_ulong) (C.cl_command_queue, error)
+	// createCommandQueueWithPropertiesKHR calls the function of the
+	// cl_khr_create_command_queue extension for OpenCL 1.2 devices.
+	createCommandQueueWithPropertiesKHR func(ctx *Context, device *Device, properties []C.cl_ulong) (C.cl_command_queue, error)
 )
 
 type CommandQueue struct {
-	clQueue C.cl_command_queue
-	device  *Device
+	clQueue  C.cl_command_queue
+	device   *Device
+	context  *Context // the context the queue was created from or nil
+	profiler *Profiler
 }
 
 func releaseCommandQueue(q *CommandQueue) {
@@ -26,11 +141,94 @@ func releaseCommandQueue(q *CommandQueue) {
 	}
 }
 
+// retainCommandQueue returns a CommandQueue for an existing
+// cl_command_queue (e.g. as returned by an info query), retaining it for
+// the lifetime of the CommandQueue.
+func retainCommandQueue(clQueue C.cl_command_queue) (*CommandQueue, error) {
+	if clQueue == nil {
+		return nil, ErrUnknown
+	}
+	var deviceId C.cl_device_id
+	if err := C.clGetCommandQueueInfo(clQueue, C.CL_QUEUE_DEVICE, C.size_t(unsafe.Sizeof(deviceId)), unsafe.Pointer(&deviceId), nil); err != C.CL_SUCCESS {
+		return nil, toError(err)
+	}
+	if err := C.clRetainCommandQueue(clQueue); err != C.CL_SUCCESS {
+		return nil, toError(err)
+	}
+	commandQueue := &CommandQueue{clQueue: clQueue, device: &Device{id: deviceId}}
+	runtime.SetFinalizer(commandQueue, releaseCommandQueue)
+	return commandQueue, nil
+}
+
 // Release calls clReleaseCommandQueue on the CommandQueue. Using the CommandQueue after Release will cause a panick.
 func (q *CommandQueue) Release() {
 	releaseCommandQueue(q)
 }
 
+func (q *CommandQueue) getInfoPointer(param C.cl_command_queue_info, value unsafe.Pointer, size int) error {
+	return toError(C.clGetCommandQueueInfo(q.clQueue, param, C.size_t(size), value, nil))
+}
+
+// Context returns the context the queue was created in.
+func (q *CommandQueue) Context() (*Context, error) {
+	var clContext C.cl_context
+	if err := q.getInfoPointer(C.CL_QUEUE_CONTEXT, unsafe.Pointer(&clContext), int(unsafe.Sizeof(clContext))); err != nil {
+		return nil, err
+	}
+	return retainContext(clContext)
+}
+
+// Device returns the device the queue was created for.
+func (q *CommandQueue) Device() *Device {
+	return q.device
+}
+
+// Properties returns the properties the queue was created with, e.g. to
+// check for CommandQueueProfilingEnable.
+func (q *CommandQueue) Properties() (CommandQueueProperty, error) {
+	var properties C.cl_command_queue_properties
+	err := q.getInfoPointer(C.CL_QUEUE_PROPERTIES, unsafe.Pointer(&properties), int(unsafe.Sizeof(properties)))
+	return CommandQueueProperty(properties), err
+}
+
+// ReferenceCount returns the reference count of the queue. The value
+// should be considered immediately stale and is mainly useful for
+// identifying leaks.
+func (q *CommandQueue) ReferenceCount() (int, error) {
+	var count C.cl_uint
+	err := q.getInfoPointer(C.CL_QUEUE_REFERENCE_COUNT, unsafe.Pointer(&count), int(unsafe.Sizeof(count)))
+	return int(count), err
+}
+
+// Size returns the size in bytes of an on-device queue. It returns
+// ErrUnsupported if the device doesn't support OpenCL 2.0 and
+// ErrInvalidCommandQueue for host queues.
+func (q *CommandQueue) Size() (int, error) {
+	if !q.device.versionAtLeast(2, 0) {
+		return 0, ErrUnsupported
+	}
+	var size C.cl_uint
+	err := q.getInfoPointer(C.CL_QUEUE_SIZE, unsafe.Pointer(&size), int(unsafe.Sizeof(size)))
+	return int(size), err
+}
+
+// DeviceDefault returns the default on-device queue of the device of the
+// queue or nil if there is none. It returns ErrUnsupported if the device
+// doesn't support OpenCL 2.1.
+func (q *CommandQueue) DeviceDefault() (*CommandQueue, error) {
+	if !q.device.versionAtLeast(2, 1) {
+		return nil, ErrUnsupported
+	}
+	var clQueue C.cl_command_queue
+	if err := q.getInfoPointer(C.CL_QUEUE_DEVICE_DEFAULT, unsafe.Pointer(&clQueue), int(unsafe.Sizeof(clQueue))); err != nil {
+		return nil, err
+	}
+	if clQueue == nil {
+		return nil, nil
+	}
+	return retainCommandQueue(clQueue)
+}
+
 // Finish blocks until all previously queued OpenCL commands in a command-queue are issued to the associated device and have completed.
 func (q *CommandQueue) Finish() error {
 	return toError(C.clFinish(q.clQueue))
@@ -38,7 +236,7 @@ func (q *CommandQueue) Finish() error {
 
 // Flush issues all previously queued OpenCL commands in a command-queue to the device associated with the command-queue.
 func (q *CommandQueue) Flush() error {
-	return toError(C.clFinish(q.clQueue))
+	return toError(C.clFlush(q.clQueue))
 }
 
 // EnqueueMapBuffer enqueues a command to map a region of the buffer object given by buffer into the host address space and returns a pointer to this mapped region.
@@ -53,6 +251,7 @@ func (q *CommandQueue) EnqueueMapBuffer(buffer *MemObject, blocking bool, flags
 	if ptr == nil {
 		return nil, ev, ErrUnknown
 	}
+	q.profile(ev, nil, CommandTypeMapBuffer, "", size)
 	return &MappedMemObject{ptr: ptr, size: size}, ev, nil
 }
 
@@ -72,6 +271,7 @@ func (q *CommandQueue) EnqueueMapImage(buffer *MemObject, blocking bool, flags M
 		return nil, ev, ErrUnknown
 	}
 	size := 0 // TODO: could calculate this
+	q.profile(ev, nil, CommandTypeMapImage, "", size)
 	return &MappedMemObject{ptr: ptr, size: size, rowPitch: int(rowPitch), slicePitch: int(slicePitch)}, ev, nil
 }
 
@@ -81,21 +281,21 @@ func (q *CommandQueue) EnqueueUnmapMemObject(buffer *MemObject, mappedObj *Mappe
 	if err := C.clEnqueueUnmapMemObject(q.clQueue, buffer.clMem, mappedObj.ptr, C.cl_uint(len(eventWaitList)), eventListPtr(eventWaitList), &event); err != C.CL_SUCCESS {
 		return nil, toError(err)
 	}
-	return newEvent(event), nil
+	return q.profile(newEvent(event), nil, CommandTypeUnmapMemObject, "", mappedObj.size)
 }
 
 // EnqueueCopyBuffer enqueues a command to copy a buffer object to another buffer object.
 func (q *CommandQueue) EnqueueCopyBuffer(srcBuffer, dstBuffer *MemObject, srcOffset, dstOffset, byteCount int, eventWaitList []*Event) (*Event, error) {
 	var event C.cl_event
 	err := toError(C.clEnqueueCopyBuffer(q.clQueue, srcBuffer.clMem, dstBuffer.clMem, C.size_t(srcOffset), C.size_t(dstOffset), C.size_t(byteCount), C.cl_uint(len(eventWaitList)), eventListPtr(eventWaitList), &event))
-	return newEvent(event), err
+	return q.profile(newEvent(event), err, CommandTypeCopyBuffer, "", byteCount)
 }
 
 // EnqueueWriteBuffer enqueues commands to write to a buffer object from host memory.
 func (q *CommandQueue) EnqueueWriteBuffer(buffer *MemObject, blocking bool, offset, dataSize int, dataPtr unsafe.Pointer, eventWaitList []*Event) (*Event, error) {
 	var event C.cl_event
 	err := toError(C.clEnqueueWriteBuffer(q.clQueue, buffer.clMem, clBool(blocking), C.size_t(offset), C.size_t(dataSize), dataPtr, C.cl_uint(len(eventWaitList)), eventListPtr(eventWaitList), &event))
-	return newEvent(event), err
+	return q.profile(newEvent(event), err, CommandTypeWriteBuffer, "", dataSize)
 }
 
 func (q *CommandQueue) EnqueueWriteBufferFloat32(buffer *MemObject, blocking bool, offset int, data []float32, eventWaitList []*Event) (*Event, error) {
@@ -108,7 +308,7 @@ func (q *CommandQueue) EnqueueWriteBufferFloat32(buffer *MemObject, blocking boo
 func (q *CommandQueue) EnqueueReadBuffer(buffer *MemObject, blocking bool, offset, dataSize int, dataPtr unsafe.Pointer, eventWaitList []*Event) (*Event, error) {
 	var event C.cl_event
 	err := toError(C.clEnqueueReadBuffer(q.clQueue, buffer.clMem, clBool(blocking), C.size_t(offset), C.size_t(dataSize), dataPtr, C.cl_uint(len(eventWaitList)), eventListPtr(eventWaitList), &event))
-	return newEvent(event), err
+	return q.profile(newEvent(event), err, CommandTypeReadBuffer, "", dataSize)
 }
 
 func (q *CommandQueue) EnqueueReadBufferFloat32(buffer *MemObject, blocking bool, offset int, data []float32, eventWaitList []*Event) (*Event, error) {
@@ -117,6 +317,28 @@ func (q *CommandQueue) EnqueueReadBufferFloat32(buffer *MemObject, blocking bool
 	return q.EnqueueReadBuffer(buffer, blocking, offset, dataSize, dataPtr, eventWaitList)
 }
 
+// EnqueueWriteBufferSlice enqueues a command to write a slice of numbers,
+// vectors such as Float4, or arrays of them to a buffer object. Slices of
+// structs and bools are rejected as for CreateBufferSlice.
+func (q *CommandQueue) EnqueueWriteBufferSlice(buffer *MemObject, blocking bool, offset int, slice interface{}, eventWaitList []*Event) (*Event, error) {
+	dataPtr, dataSize, err := slicePointer(slice)
+	if err != nil {
+		return nil, err
+	}
+	return q.EnqueueWriteBuffer(buffer, blocking, offset, dataSize, dataPtr, eventWaitList)
+}
+
+// EnqueueReadBufferSlice enqueues a command to read from a buffer object
+// into a slice of numbers, vectors such as Float4, or arrays of them.
+// Slices of structs and bools are rejected as for CreateBufferSlice.
+func (q *CommandQueue) EnqueueReadBufferSlice(buffer *MemObject, blocking bool, offset int, slice interface{}, eventWaitList []*Event) (*Event, error) {
+	dataPtr, dataSize, err := slicePointer(slice)
+	if err != nil {
+		return nil, err
+	}
+	return q.EnqueueReadBuffer(buffer, blocking, offset, dataSize, dataPtr, eventWaitList)
+}
+
 // EnqueueNDRangeKernel enqueues a command to execute a kernel on a device.
 func (q *CommandQueue) EnqueueNDRangeKernel(kernel *Kernel, globalWorkOffset, globalWorkSize, localWorkSize []int, eventWaitList []*Event) (*Event, error) {
 	workDim := len(globalWorkSize)
@@ -149,7 +371,7 @@ func (q *CommandQueue) EnqueueNDRangeKernel(kernel *Kernel, globalWorkOffset, gl
 	}
 	var event C.cl_event
 	err := toError(C.clEnqueueNDRangeKernel(q.clQueue, kernel.clKernel, C.cl_uint(workDim), globalWorkOffsetPtr, globalWorkSizePtr, localWorkSizePtr, C.cl_uint(len(eventWaitList)), eventListPtr(eventWaitList), &event))
-	return newEvent(event), err
+	return q.profile(newEvent(event), err, CommandTypeNDRangeKernel, kernel.name, 0)
 }
 
 // EnqueueReadImage enqueues a command to read from a 2D or 3D image object to host memory.
@@ -158,7 +380,7 @@ func (q *CommandQueue) EnqueueReadImage(image *MemObject, blocking bool, origin,
 	cRegion := sizeT3(region)
 	var event C.cl_event
 	err := toError(C.clEnqueueReadImage(q.clQueue, image.clMem, clBool(blocking), &cOrigin[0], &cRegion[0], C.size_t(rowPitch), C.size_t(slicePitch), unsafe.Pointer(&data[0]), C.cl_uint(len(eventWaitList)), eventListPtr(eventWaitList), &event))
-	return newEvent(event), err
+	return q.profile(newEvent(event), err, CommandTypeReadImage, "", len(data))
 }
 
 // EnqueueWriteImage enqueues a command to write from a 2D or 3D image object to host memory.
@@ -167,5 +389,5 @@ func (q *CommandQueue) EnqueueWriteImage(image *MemObject, blocking bool, origin
 	cRegion := sizeT3(region)
 	var event C.cl_event
 	err := toError(C.clEnqueueWriteImage(q.clQueue, image.clMem, clBool(blocking), &cOrigin[0], &cRegion[0], C.size_t(rowPitch), C.size_t(slicePitch), unsafe.Pointer(&data[0]), C.cl_uint(len(eventWaitList)), eventListPtr(eventWaitList), &event))
-	return newEvent(event), err
+	return q.profile(newEvent(event), err, CommandTypeWriteImage, "", len(data))
 }
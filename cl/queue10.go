@@ -0,0 +1,126 @@
+// +build cl10
+
+package cl
+
+// #include "cl.h"
+import "C"
+
+import (
+	"fmt"
+	"unsafe"
+)
+
+// EnqueueMarker enqueues a marker command. Its event completes when all
+// commands enqueued before it have completed.
+func (q *CommandQueue) EnqueueMarker() (*Event, error) {
+	var event C.cl_event
+	err := toError(C.clEnqueueMarker(q.clQueue, &event))
+	return q.profile(newEvent(event), err, CommandTypeMarker, "", 0)
+}
+
+// EnqueueBarrier enqueues a barrier that makes commands enqueued after it
+// wait for the commands enqueued before it to complete.
+func (q *CommandQueue) EnqueueBarrier() error {
+	return toError(C.clEnqueueBarrier(q.clQueue))
+}
+
+// EnqueueWaitForEvents makes commands enqueued after it wait for events to
+// complete.
+func (q *CommandQueue) EnqueueWaitForEvents(events []*Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+	return toError(C.clEnqueueWaitForEvents(q.clQueue, C.cl_uint(len(events)), eventListPtr(events)))
+}
+
+// EnqueueBarrierWithWaitList emulates the OpenCL 1.2 command with
+// EnqueueWaitForEvents, EnqueueBarrier and EnqueueMarker. The returned event
+// completes once eventWaitList (or, if empty, all previously enqueued
+// commands) has completed.
+func (q *CommandQueue) EnqueueBarrierWithWaitList(eventWaitList []*Event) (*Event, error) {
+	if err := q.EnqueueWaitForEvents(eventWaitList); err != nil {
+		return nil, err
+	}
+	if err := q.EnqueueBarrier(); err != nil {
+		return nil, err
+	}
+	return q.EnqueueMarker()
+}
+
+// EnqueueMarkerWithWaitList emulates the OpenCL 1.2 command with
+// EnqueueWaitForEvents and EnqueueMarker. Unlike the 1.2 marker, a
+// non-empty eventWaitList also blocks the commands enqueued after it.
+func (q *CommandQueue) EnqueueMarkerWithWaitList(eventWaitList []*Event) (*Event, error) {
+	if err := q.EnqueueWaitForEvents(eventWaitList); err != nil {
+		return nil, err
+	}
+	return q.EnqueueMarker()
+}
+
+const fillBufferSource = `
+__kernel void fill_buffer(__global uchar* buffer, __global const uchar* pattern, uint patternSize, ulong offset)
+{
+	__global uchar* dst = buffer + offset + get_global_id(0) * patternSize;
+	for (uint i = 0; i < patternSize; i++)
+		dst[i] = pattern[i];
+}
+`
+
+// EnqueueFillBuffer emulates the OpenCL 1.2 command with a kernel that's
+// compiled the first time it's used in a context and released with the
+// context. As in OpenCL 1.2 patternSize must be a power of two up to 128,
+// and offset and size must be multiples of it. The command is recorded as
+// the kernel fill_buffer by a Profiler.
+func (q *CommandQueue) EnqueueFillBuffer(buffer *MemObject, pattern unsafe.Pointer, patternSize, offset, size int, eventWaitList []*Event) (*Event, error) {
+	if patternSize <= 0 || patternSize > 128 || patternSize&(patternSize-1) != 0 || offset < 0 || offset%patternSize != 0 || size%patternSize != 0 {
+		return nil, ErrInvalidValue
+	}
+	if size == 0 {
+		return q.EnqueueMarkerWithWaitList(eventWaitList)
+	}
+	context := q.context
+	if context == nil {
+		// The queue wasn't created from a Context (e.g. it was returned
+		// by Event.CommandQueue), so the kernel is only used once.
+		var err error
+		if context, err = q.Context(); err != nil {
+			return nil, err
+		}
+		defer context.Release()
+	}
+	patternBuffer, err := context.CreateBufferUnsafe(MemReadOnly|MemCopyHostPtr, patternSize, pattern)
+	if err != nil {
+		return nil, err
+	}
+	// The buffer is deleted once the fill has completed.
+	defer patternBuffer.Release()
+
+	context.fillMu.Lock()
+	defer context.fillMu.Unlock()
+	if context.fillKernel == nil {
+		if context.fillKernel, err = context.createFillKernel(); err != nil {
+			return nil, err
+		}
+	}
+	kernel := context.fillKernel
+	if err := kernel.SetArgs(buffer, patternBuffer, uint32(patternSize), uint64(offset)); err != nil {
+		return nil, err
+	}
+	return q.EnqueueNDRangeKernel(kernel, nil, []int{size / patternSize}, nil, eventWaitList)
+}
+
+func (ctx *Context) createFillKernel() (*Kernel, error) {
+	program, err := ctx.CreateProgramWithSource([]string{fillBufferSource})
+	if err != nil {
+		return nil, err
+	}
+	if err := program.BuildProgram(nil, ""); err != nil {
+		program.Release()
+		return nil, fmt.Errorf("cl: building the fill kernel failed: %s", err)
+	}
+	kernel, err := program.CreateKernel("fill_buffer")
+	if err != nil {
+		program.Release()
+	}
+	return kernel, err
+}
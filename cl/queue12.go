@@ -3,27 +3,62 @@
 package cl
 
 // #include "cl.h"
+//
+// typedef cl_command_queue (CL_API_CALL *createCommandQueueWithPropertiesKHRFunc)(cl_context, cl_device_id, const cl_ulong *, cl_int *);
+//
+// static cl_command_queue callCreateCommandQueueWithPropertiesKHR(void *fn, cl_context context, cl_device_id device, const cl_ulong *properties, cl_int *err) {
+// 	return ((createCommandQueueWithPropertiesKHRFunc)fn)(context, device, properties, err);
+// }
 import "C"
 
-import "unsafe"
+import (
+	"strings"
+	"unsafe"
+)
+
+func init() {
+	createCommandQueueWithPropertiesKHR = createCommandQueueWithPropertiesFromExtension
+}
 
 // EnqueueFillBuffer enqueues a command to fill a buffer object with a pattern of a given pattern size.
 func (q *CommandQueue) EnqueueFillBuffer(buffer *MemObject, pattern unsafe.Pointer, patternSize, offset, size int, eventWaitList []*Event) (*Event, error) {
 	var event C.cl_event
 	err := toError(C.clEnqueueFillBuffer(q.clQueue, buffer.clMem, pattern, C.size_t(patternSize), C.size_t(offset), C.size_t(size), C.cl_uint(len(eventWaitList)), eventListPtr(eventWaitList), &event))
-	return newEvent(event), err
+	return q.profile(newEvent(event), err, CommandTypeFillBuffer, "", size)
 }
 
 // EnqueueBarrierWithWaitList enqueues a synchronization point that enqueues a barrier operation.
 func (q *CommandQueue) EnqueueBarrierWithWaitList(eventWaitList []*Event) (*Event, error) {
 	var event C.cl_event
 	err := toError(C.clEnqueueBarrierWithWaitList(q.clQueue, C.cl_uint(len(eventWaitList)), eventListPtr(eventWaitList), &event))
-	return newEvent(event), err
+	return q.profile(newEvent(event), err, CommandTypeBarrier, "", 0)
 }
 
 // EnqueueMarkerWithWaitList enqueues a marker command which waits for either a list of events to complete, or all previously enqueued commands to complete.
 func (q *CommandQueue) EnqueueMarkerWithWaitList(eventWaitList []*Event) (*Event, error) {
 	var event C.cl_event
 	err := toError(C.clEnqueueMarkerWithWaitList(q.clQueue, C.cl_uint(len(eventWaitList)), eventListPtr(eventWaitList), &event))
-	return newEvent(event), err
+	return q.profile(newEvent(event), err, CommandTypeMarker, "", 0)
+}
+
+// createCommandQueueWithPropertiesFromExtension calls
+// clCreateCommandQueueWithPropertiesKHR from the cl_khr_create_command_queue
+// extension.
+func createCommandQueueWithPropertiesFromExtension(ctx *Context, device *Device, properties []C.cl_ulong) (C.cl_command_queue, error) {
+	if !strings.Contains(" "+device.Extensions()+" ", " cl_khr_create_command_queue ") {
+		return nil, ErrUnsupported
+	}
+	fn := device.Platform().extensionFunctionAddress("clCreateCommandQueueWithPropertiesKHR")
+	if fn == nil {
+		return nil, ErrUnsupported
+	}
+	var err C.cl_int
+	clQueue := C.callCreateCommandQueueWithPropertiesKHR(fn, ctx.clContext, device.id, &properties[0], &err)
+	if err != C.CL_SUCCESS {
+		return nil, toError(err)
+	}
+	if clQueue == nil {
+		return nil, ErrUnknown
+	}
+	return clQueue, nil
 }
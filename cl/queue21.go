@@ -0,0 +1,21 @@
+// +build cl21 cl22
+
+package cl
+
+// #include "cl.h"
+import "C"
+import "unsafe"
+
+func init() {
+	createCommandQueueWithProperties = func(ctx *Context, device *Device, properties []C.cl_ulong) (C.cl_command_queue, error) {
+		var err C.cl_int
+		clQueue := C.clCreateCommandQueueWithProperties(ctx.clContext, device.id, (*C.cl_queue_properties)(unsafe.Pointer(&properties[0])), &err)
+		if err != C.CL_SUCCESS {
+			return nil, toError(err)
+		}
+		if clQueue == nil {
+			return nil, ErrUnknown
+		}
+		return clQueue, nil
+	}
+}
@@ -0,0 +1,256 @@
+package cl
+
+import (
+	"bufio"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// IncludeOptions controls how CreateProgramFromFS resolves #include directives.
+type IncludeOptions struct {
+	// IncludeDirs are searched in order for #include <...> and for
+	// #include "..." that isn't found relative to the including file.
+	IncludeDirs []string
+}
+
+// SourceMap maps lines of a preprocessed program source back to the
+// file and line they came from.
+type SourceMap struct {
+	segments []sourceSegment
+}
+
+type sourceSegment struct {
+	line     int // first line in the preprocessed source (1-based)
+	file     string
+	fileLine int // line in file corresponding to line
+}
+
+// Lookup returns the original file and line for a line (1-based) of the
+// preprocessed source.
+func (m *SourceMap) Lookup(line int) (string, int, bool) {
+	if m == nil || line < 1 {
+		return "", 0, false
+	}
+	i := sort.Search(len(m.segments), func(i int) bool { return m.segments[i].line > line }) - 1
+	if i < 0 {
+		return "", 0, false
+	}
+	seg := m.segments[i]
+	return seg.file, seg.fileLine + line - seg.line, true
+}
+
+var (
+	// <source>:12:5: error: ... and similar as used by clang based compilers
+	buildLogLocationRE = regexp.MustCompile(`(?m)^([^\s:"]*):(\d+):`)
+	// "/tmp/OCL123.cl", line 12: error: ...
+	buildLogQuotedLocationRE = regexp.MustCompile(`(?m)^"([^"]*)", line (\d+):`)
+)
+
+// Rewrite replaces locations in a compiler build log with the original
+// file and line.
+func (m *SourceMap) Rewrite(log string) string {
+	if m == nil {
+		return log
+	}
+	log = buildLogLocationRE.ReplaceAllStringFunc(log, func(s string) string {
+		parts := buildLogLocationRE.FindStringSubmatch(s)
+		if file, line, ok := m.lookupString(parts[2]); ok {
+			return fmt.Sprintf("%s:%d:", file, line)
+		}
+		return s
+	})
+	return buildLogQuotedLocationRE.ReplaceAllStringFunc(log, func(s string) string {
+		parts := buildLogQuotedLocationRE.FindStringSubmatch(s)
+		if file, line, ok := m.lookupString(parts[2]); ok {
+			return fmt.Sprintf("%q, line %d:", file, line)
+		}
+		return s
+	})
+}
+
+func (m *SourceMap) lookupString(line string) (string, int, bool) {
+	n, err := strconv.Atoi(line)
+	if err != nil {
+		return "", 0, false
+	}
+	return m.Lookup(n)
+}
+
+// CreateProgramFromFS creates a program from the source file entry in fsys.
+// #include directives are resolved from fsys before the source is given to
+// the compiler, so that kernels can be embedded in the binary (e.g. using
+// embed.FS). Includes that can't be found in fsys are left for the
+// compiler to resolve. Files using #pragma once or a classic
+// #ifndef/#define include guard are only included once.
+//
+// Build errors returned by BuildProgram refer to the original files and
+// lines.
+func (ctx *Context) CreateProgramFromFS(fsys fs.FS, entry string, opts *IncludeOptions) (*Program, error) {
+	source, sourceMap, err := preprocessFS(fsys, entry, opts)
+	if err != nil {
+		return nil, err
+	}
+	program, err := ctx.CreateProgramWithSource([]string{source})
+	if err != nil {
+		return nil, err
+	}
+	program.sourceMap = sourceMap
+	return program, nil
+}
+
+var (
+	includeRE     = regexp.MustCompile(`^\s*#\s*include\s*([<"])([^>"]+)[>"]`)
+	pragmaOnceRE  = regexp.MustCompile(`^\s*#\s*pragma\s+once\b`)
+	guardIfndefRE = regexp.MustCompile(`^\s*#\s*ifndef\s+(\w+)`)
+	guardDefineRE = regexp.MustCompile(`^\s*#\s*define\s+(\w+)`)
+	guardEndifRE  = regexp.MustCompile(`^\s*#\s*endif\b`)
+)
+
+type preprocessor struct {
+	fsys        fs.FS
+	includeDirs []string
+	out         strings.Builder
+	outLine     int
+	sourceMap   *SourceMap
+	once        map[string]bool // files to include at most once
+	active      map[string]bool // files currently being included
+}
+
+func preprocessFS(fsys fs.FS, entry string, opts *IncludeOptions) (string, *SourceMap, error) {
+	pp := &preprocessor{
+		fsys:      fsys,
+		sourceMap: &SourceMap{},
+		once:      make(map[string]bool),
+		active:    make(map[string]bool),
+	}
+	if opts != nil {
+		pp.includeDirs = opts.IncludeDirs
+	}
+	if err := pp.include(path.Clean(entry)); err != nil {
+		return "", nil, err
+	}
+	return pp.out.String(), pp.sourceMap, nil
+}
+
+func (pp *preprocessor) include(name string) error {
+	if pp.once[name] {
+		return nil
+	}
+	if pp.active[name] {
+		return fmt.Errorf("cl: recursive #include of %s", name)
+	}
+	data, err := fs.ReadFile(pp.fsys, name)
+	if err != nil {
+		return err
+	}
+	var lines []string
+	scanner := bufio.NewScanner(strings.NewReader(string(data)))
+	scanner.Buffer(nil, len(data)+1)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	if hasIncludeGuard(lines) {
+		pp.once[name] = true
+	}
+	pp.active[name] = true
+	defer delete(pp.active, name)
+
+	pp.mapLine(name, 1)
+	for i, line := range lines {
+		if pragmaOnceRE.MatchString(line) {
+			pp.once[name] = true
+			pp.emit("")
+			continue
+		}
+		if m := includeRE.FindStringSubmatch(line); m != nil {
+			if inc := pp.resolve(name, m[1] == "\"", m[2]); inc != "" {
+				if err := pp.include(inc); err != nil {
+					return err
+				}
+				pp.mapLine(name, i+2)
+				continue
+			}
+		}
+		pp.emit(line)
+	}
+	return nil
+}
+
+// resolve returns the path in fsys of an included file or "" if not found.
+func (pp *preprocessor) resolve(from string, quoted bool, name string) string {
+	var candidates []string
+	if quoted {
+		candidates = append(candidates, path.Join(path.Dir(from), name))
+	}
+	for _, dir := range pp.includeDirs {
+		candidates = append(candidates, path.Join(dir, name))
+	}
+	for _, c := range candidates {
+		if st, err := fs.Stat(pp.fsys, c); err == nil && !st.IsDir() {
+			return c
+		}
+	}
+	return ""
+}
+
+// mapLine records that the next output line is line fileLine of file.
+func (pp *preprocessor) mapLine(file string, fileLine int) {
+	seg := sourceSegment{line: pp.outLine + 1, file: file, fileLine: fileLine}
+	segs := pp.sourceMap.segments
+	if n := len(segs); n > 0 && segs[n-1].line == seg.line {
+		segs[n-1] = seg
+	} else {
+		pp.sourceMap.segments = append(segs, seg)
+	}
+}
+
+func (pp *preprocessor) emit(line string) {
+	pp.out.WriteString(line)
+	pp.out.WriteByte('\n')
+	pp.outLine++
+}
+
+// hasIncludeGuard reports whether the contents of the file, apart from
+// comments, are wrapped in #ifndef X, #define X ... #endif.
+func hasIncludeGuard(lines []string) bool {
+	first, last := -1, -1
+	for i, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), "#") {
+			if first < 0 {
+				first = i
+			}
+			last = i
+		}
+	}
+	if first < 0 || first == last {
+		return false
+	}
+	for i, line := range lines {
+		if i >= first && i <= last {
+			continue
+		}
+		if t := strings.TrimSpace(line); t != "" && !isCommentLine(t) {
+			return false
+		}
+	}
+	ifndef := guardIfndefRE.FindStringSubmatch(lines[first])
+	if ifndef == nil || !guardEndifRE.MatchString(lines[last]) {
+		return false
+	}
+	for _, line := range lines[first+1 : last] {
+		if t := strings.TrimSpace(line); strings.HasPrefix(t, "#") {
+			define := guardDefineRE.FindStringSubmatch(t)
+			return define != nil && define[1] == ifndef[1]
+		}
+	}
+	return false
+}
+
+func isCommentLine(line string) bool {
+	return strings.HasPrefix(line, "//") || strings.HasPrefix(line, "/*") || strings.HasPrefix(line, "*")
+}
@@ -0,0 +1,54 @@
+package cl
+
+import (
+	"testing"
+	"testing/fstest"
+)
+
+func TestPreprocessFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"kernels/main.cl":  {Data: []byte("#include \"common.h\"\n#include <util.h>\n#include \"common.h\"\n#include <opencl-c-missing.h>\n__kernel void k() {\n}\n")},
+		"kernels/common.h": {Data: []byte("// shared definitions\n#ifndef COMMON_H\n#define COMMON_H\n#define N 16\n#endif // COMMON_H\n")},
+		"include/util.h":   {Data: []byte("#pragma once\nfloat twice(float x) { return 2 * x; }\n")},
+	}
+	source, sourceMap, err := preprocessFS(fsys, "kernels/main.cl", &IncludeOptions{IncludeDirs: []string{"include"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "// shared definitions\n#ifndef COMMON_H\n#define COMMON_H\n#define N 16\n#endif // COMMON_H\n" +
+		"\nfloat twice(float x) { return 2 * x; }\n" +
+		"#include <opencl-c-missing.h>\n__kernel void k() {\n}\n"
+	if source != expected {
+		t.Fatalf("expected:\n%s\ngot:\n%s", expected, source)
+	}
+	lines := []struct {
+		line     int
+		file     string
+		fileLine int
+	}{
+		{1, "kernels/common.h", 1},
+		{4, "kernels/common.h", 4},
+		{7, "include/util.h", 2},
+		{8, "kernels/main.cl", 4},
+		{10, "kernels/main.cl", 6},
+	}
+	for _, l := range lines {
+		if file, line, ok := sourceMap.Lookup(l.line); !ok || file != l.file || line != l.fileLine {
+			t.Errorf("Lookup(%d) = %s:%d, expected %s:%d", l.line, file, line, l.file, l.fileLine)
+		}
+	}
+	log := sourceMap.Rewrite("<source>:9:12: error: use of undeclared identifier\n\"/tmp/OCL1.cl\", line 4: warning\n")
+	if expected := "kernels/main.cl:5:12: error: use of undeclared identifier\n\"kernels/common.h\", line 4: warning\n"; log != expected {
+		t.Errorf("expected rewritten log %q got %q", expected, log)
+	}
+}
+
+func TestPreprocessFSRecursive(t *testing.T) {
+	fsys := fstest.MapFS{
+		"a.cl": {Data: []byte("#include \"b.h\"\n")},
+		"b.h":  {Data: []byte("#include \"a.cl\"\n")},
+	}
+	if _, _, err := preprocessFS(fsys, "a.cl", nil); err == nil {
+		t.Fatal("expected error for recursive include")
+	}
+}
@@ -0,0 +1,370 @@
+package cl
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"unsafe"
+)
+
+// structTag is the parsed form of a `cl` struct field tag.
+type structTag struct {
+	name   string
+	skip   bool
+	packed bool
+	align  int
+}
+
+func parseStructTag(tag string) (structTag, error) {
+	var st structTag
+	if tag == "-" {
+		st.skip = true
+		return st, nil
+	}
+	parts := strings.Split(tag, ",")
+	st.name = parts[0]
+	if st.name == "-" {
+		return st, fmt.Errorf("options can't be combined with %q", st.name)
+	}
+	for _, opt := range parts[1:] {
+		switch {
+		case opt == "packed":
+			st.packed = true
+		case strings.HasPrefix(opt, "align="):
+			n, err := strconv.Atoi(opt[len("align="):])
+			if err != nil || n <= 0 || n&(n-1) != 0 {
+				return st, fmt.Errorf("invalid alignment %q", opt)
+			}
+			st.align = n
+		case opt == "":
+		default:
+			return st, fmt.Errorf("unknown option %q", opt)
+		}
+	}
+	return st, nil
+}
+
+// typeLayout is the OpenCL C memory layout of a Go type.
+type typeLayout struct {
+	typ    reflect.Type
+	clType string // OpenCL C type name (for structs the Go type name)
+	size   int
+	align  int
+	elem   *typeLayout // element layout for arrays
+	length int         // array length
+	fields []fieldLayout
+	packed bool
+}
+
+type fieldLayout struct {
+	name   string // OpenCL C name of the field
+	index  int    // index of the field in the Go struct
+	offset int
+	align  int // explicit alignment from the tag or 0
+	layout *typeLayout
+}
+
+var (
+	vectorType   = reflect.TypeOf((*Vector)(nil)).Elem()
+	layoutCache  sync.Map // reflect.Type -> *typeLayout
+	scalarCTypes = map[reflect.Kind]string{
+		reflect.Int8:    "char",
+		reflect.Uint8:   "uchar",
+		reflect.Int16:   "short",
+		reflect.Uint16:  "ushort",
+		reflect.Int32:   "int",
+		reflect.Uint32:  "uint",
+		reflect.Int64:   "long",
+		reflect.Uint64:  "ulong",
+		reflect.Float32: "float",
+		reflect.Float64: "double",
+	}
+)
+
+// layoutOf returns the OpenCL C layout of the type t.
+func layoutOf(t reflect.Type) (*typeLayout, error) {
+	if l, ok := layoutCache.Load(t); ok {
+		return l.(*typeLayout), nil
+	}
+	l, err := computeLayout(t)
+	if err != nil {
+		return nil, err
+	}
+	layoutCache.Store(t, l)
+	return l, nil
+}
+
+func computeLayout(t reflect.Type) (*typeLayout, error) {
+	if clType, ok := scalarCTypes[t.Kind()]; ok {
+		size := int(t.Size())
+		return &typeLayout{typ: t, clType: clType, size: size, align: size}, nil
+	}
+	switch t.Kind() {
+	case reflect.Array:
+		elem, err := layoutOf(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		l := &typeLayout{typ: t, size: elem.size * t.Len(), align: elem.align, elem: elem, length: t.Len()}
+		if t.Implements(vectorType) {
+			// OpenCL vectors are aligned to their size (3-component
+			// vectors are stored as 4 components in Go as well).
+			l.clType = strings.ToLower(t.Name())
+			l.align = l.size
+		}
+		return l, nil
+	case reflect.Struct:
+		return computeStructLayout(t)
+	}
+	return nil, fmt.Errorf("cl: type %s has no OpenCL C equivalent", t)
+}
+
+func computeStructLayout(t reflect.Type) (*typeLayout, error) {
+	l := &typeLayout{typ: t, clType: t.Name(), align: 1}
+	structAlign := 0
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag, err := parseStructTag(f.Tag.Get("cl"))
+		if err != nil {
+			return nil, fmt.Errorf("cl: invalid tag on field %s.%s: %s", t, f.Name, err)
+		}
+		if f.Name == "_" {
+			if tag.name != "" {
+				return nil, fmt.Errorf("cl: invalid tag on field %s.%s: a blank field can't be named", t, f.Name)
+			}
+			l.packed = l.packed || tag.packed
+			if tag.align > structAlign {
+				structAlign = tag.align
+			}
+			continue
+		}
+		if tag.packed {
+			return nil, fmt.Errorf("cl: invalid tag on field %s.%s: packed is only allowed on a blank field", t, f.Name)
+		}
+		if tag.skip {
+			continue
+		}
+		if f.PkgPath != "" {
+			return nil, fmt.Errorf("cl: unexported field %s.%s can't be used in an OpenCL struct", t, f.Name)
+		}
+		fl, err := layoutOf(f.Type)
+		if err != nil {
+			return nil, fmt.Errorf("cl: unsupported field %s.%s: %s", t, f.Name, strings.TrimPrefix(err.Error(), "cl: "))
+		}
+		name := f.Name
+		if tag.name != "" {
+			name = tag.name
+		}
+		l.fields = append(l.fields, fieldLayout{name: name, index: i, align: tag.align, layout: fl})
+	}
+	if len(l.fields) == 0 {
+		return nil, fmt.Errorf("cl: struct %s has no fields", t)
+	}
+	offset := 0
+	for i := range l.fields {
+		f := &l.fields[i]
+		align := f.layout.align
+		if l.packed {
+			align = 1
+		}
+		if f.align > align {
+			align = f.align
+		}
+		offset = alignUp(offset, align)
+		f.offset = offset
+		offset += f.layout.size
+		if align > l.align {
+			l.align = align
+		}
+	}
+	if structAlign > l.align {
+		l.align = structAlign
+	}
+	l.size = alignUp(offset, l.align)
+	return l, nil
+}
+
+func alignUp(n, align int) int {
+	return (n + align - 1) / align * align
+}
+
+// encode writes v to buf (which must be at least l.size long) using the
+// byte order of the device.
+func (l *typeLayout) encode(buf []byte, v reflect.Value, order binary.ByteOrder) {
+	switch v.Kind() {
+	case reflect.Int8, reflect.Uint8:
+		if v.Kind() == reflect.Int8 {
+			buf[0] = byte(v.Int())
+		} else {
+			buf[0] = byte(v.Uint())
+		}
+	case reflect.Int16:
+		order.PutUint16(buf, uint16(v.Int()))
+	case reflect.Uint16:
+		order.PutUint16(buf, uint16(v.Uint()))
+	case reflect.Int32:
+		order.PutUint32(buf, uint32(v.Int()))
+	case reflect.Uint32:
+		order.PutUint32(buf, uint32(v.Uint()))
+	case reflect.Int64:
+		order.PutUint64(buf, uint64(v.Int()))
+	case reflect.Uint64:
+		order.PutUint64(buf, v.Uint())
+	case reflect.Float32:
+		order.PutUint32(buf, math.Float32bits(float32(v.Float())))
+	case reflect.Float64:
+		order.PutUint64(buf, math.Float64bits(v.Float()))
+	case reflect.Array:
+		for i := 0; i < l.length; i++ {
+			l.elem.encode(buf[i*l.elem.size:], v.Index(i), order)
+		}
+	case reflect.Struct:
+		for _, f := range l.fields {
+			f.layout.encode(buf[f.offset:], v.Field(f.index), order)
+		}
+	}
+}
+
+// SetArgStruct sets an argument declared as a struct passed by value. The
+// Go struct v (or pointer to struct) is laid out using the OpenCL C
+// alignment and padding rules in the byte order of the device. Supported
+// field types are the fixed size integer and float types, vectors (Float4,
+// etc.), and arrays and structs of them.
+//
+// Fields can be annotated with a tag of the form `cl:"name,option,..."`
+// where name overrides the field name used in OpenCL C (see
+// StructDeclarations) and the options are:
+//
+//	align=N  align the field to N bytes like __attribute__((aligned(N)))
+//	packed   on a blank field (`_ struct{} `cl:",packed"``) lays out the
+//	         struct without padding like __attribute__((packed))
+//
+// A blank field with align=N aligns the whole struct to N bytes. Fields
+// tagged with "-" are ignored. Unknown or misplaced options (e.g. packed on
+// a named field) are reported as errors.
+func (k *Kernel) SetArgStruct(index int, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Ptr && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return ErrUnsupportedArgumentType{Index: index, Value: v}
+	}
+	layout, err := layoutOf(rv.Type())
+	if err != nil {
+		return err
+	}
+	order, err := k.deviceByteOrder()
+	if err != nil {
+		return err
+	}
+	buf := make([]byte, layout.size)
+	layout.encode(buf, rv, order)
+	return k.SetArgUnsafe(index, len(buf), unsafe.Pointer(&buf[0]))
+}
+
+// StructDeclarations generates OpenCL C typedefs matching the layout of
+// the given Go structs (values or pointers to structs), including structs
+// used by their fields. Every struct is declared with its explicit
+// alignment and followed by compile time checks of its size and field
+// offsets so that a mismatch between the host and device layouts fails
+// the build. The result can be passed as the first source to
+// CreateProgramWithSource.
+func StructDeclarations(structs ...interface{}) (string, error) {
+	var buf bytes.Buffer
+	var layouts []*typeLayout
+	seen := make(map[reflect.Type]bool)
+	var add func(l *typeLayout) error
+	add = func(l *typeLayout) error {
+		if seen[l.typ] {
+			return nil
+		}
+		seen[l.typ] = true
+		if l.typ.Name() == "" {
+			return fmt.Errorf("cl: anonymous struct %s can't be declared", l.typ)
+		}
+		for _, f := range l.fields {
+			fl := f.layout
+			for fl.elem != nil && fl.clType == "" {
+				fl = fl.elem
+			}
+			if fl.fields != nil {
+				if err := add(fl); err != nil {
+					return err
+				}
+			}
+		}
+		layouts = append(layouts, l)
+		return nil
+	}
+	for _, v := range structs {
+		t := reflect.TypeOf(v)
+		if t != nil && t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		if t == nil || t.Kind() != reflect.Struct {
+			return "", fmt.Errorf("cl: StructDeclarations requires structs, got %T", v)
+		}
+		l, err := layoutOf(t)
+		if err != nil {
+			return "", err
+		}
+		if err := add(l); err != nil {
+			return "", err
+		}
+	}
+
+	for _, l := range layouts {
+		if l.usesDouble() {
+			buf.WriteString("#pragma OPENCL EXTENSION cl_khr_fp64 : enable\n\n")
+			break
+		}
+	}
+	buf.WriteString("#define CL_LAYOUT_CHECK(name, cond) typedef char name[(cond) ? 1 : -1]\n")
+	for _, l := range layouts {
+		buf.WriteString("\ntypedef struct ")
+		if l.packed {
+			buf.WriteString("__attribute__((packed)) ")
+		}
+		buf.WriteString("{\n")
+		for _, f := range l.fields {
+			elem := f.layout
+			var dims string
+			for elem.clType == "" {
+				dims += fmt.Sprintf("[%d]", elem.length)
+				elem = elem.elem
+			}
+			fmt.Fprintf(&buf, "\t%s %s%s", elem.clType, f.name, dims)
+			if f.align != 0 {
+				fmt.Fprintf(&buf, " __attribute__((aligned(%d)))", f.align)
+			}
+			buf.WriteString(";\n")
+		}
+		fmt.Fprintf(&buf, "} __attribute__((aligned(%d))) %s;\n", l.align, l.clType)
+		fmt.Fprintf(&buf, "CL_LAYOUT_CHECK(cl_layout_check_%s_size, sizeof(%s) == %d);\n", l.clType, l.clType, l.size)
+		for _, f := range l.fields {
+			fmt.Fprintf(&buf, "CL_LAYOUT_CHECK(cl_layout_check_%s_%s, __builtin_offsetof(%s, %s) == %d);\n", l.clType, f.name, l.clType, f.name, f.offset)
+		}
+	}
+	return buf.String(), nil
+}
+
+func (l *typeLayout) usesDouble() bool {
+	if l.typ.Kind() == reflect.Float64 {
+		return true
+	}
+	if l.elem != nil {
+		return l.elem.usesDouble()
+	}
+	for _, f := range l.fields {
+		if f.layout.usesDouble() {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,137 @@
+package cl
+
+import (
+	"encoding/binary"
+	"reflect"
+	"testing"
+)
+
+type testInner struct {
+	A uint8
+	B Float3
+}
+
+type testParams struct {
+	Count  int32
+	Scale  float64
+	Flags  [3]uint16
+	Inner  testInner
+	Offset Int2   `cl:"offset,align=32"`
+	Host   string `cl:"-"`
+}
+
+type testPacked struct {
+	_ struct{} `cl:",packed"`
+	A uint8
+	B uint32
+	C uint16
+}
+
+func TestStructLayout(t *testing.T) {
+	l, err := layoutOf(reflect.TypeOf(testParams{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	offsets := map[string]int{"Count": 0, "Scale": 8, "Flags": 16, "Inner": 32, "offset": 64}
+	for _, f := range l.fields {
+		if offsets[f.name] != f.offset {
+			t.Errorf("expected offset %d for %s, got %d", offsets[f.name], f.name, f.offset)
+		}
+	}
+	if l.size != 96 || l.align != 32 {
+		t.Errorf("expected size 96 align 32, got size %d align %d", l.size, l.align)
+	}
+	if inner := l.fields[3].layout; inner.size != 32 || inner.align != 16 {
+		t.Errorf("expected inner size 32 align 16, got size %d align %d", inner.size, inner.align)
+	}
+
+	l, err = layoutOf(reflect.TypeOf(testPacked{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l.size != 7 || l.align != 1 || l.fields[2].offset != 5 {
+		t.Errorf("unexpected packed layout: size %d align %d", l.size, l.align)
+	}
+	buf := make([]byte, l.size)
+	l.encode(buf, reflect.ValueOf(testPacked{A: 1, B: 0x02030405, C: 0x0607}), binary.BigEndian)
+	if expected := []byte{1, 2, 3, 4, 5, 6, 7}; !reflect.DeepEqual(buf, expected) {
+		t.Errorf("expected %v got %v", expected, buf)
+	}
+}
+
+func TestStructLayoutUnsupported(t *testing.T) {
+	types := []interface{}{
+		struct{ A int }{},
+		struct{ A bool }{},
+		struct{ A *MemObject }{},
+		struct{ A []float32 }{},
+		struct {
+			A float32 `cl:",align=3"`
+		}{},
+		struct {
+			A float32 `cl:",packed"`
+		}{},
+		struct {
+			A float32
+			_ struct{} `cl:"pad,packed"`
+		}{},
+		struct {
+			A float32 `cl:"-,align=4"`
+			B float32
+		}{},
+		struct {
+			A float32 `cl:",volatile"`
+		}{},
+	}
+	for _, v := range types {
+		if _, err := layoutOf(reflect.TypeOf(v)); err == nil {
+			t.Errorf("expected error for %T", v)
+		}
+	}
+}
+
+func TestStructDeclarations(t *testing.T) {
+	decls, err := StructDeclarations(&testParams{}, testPacked{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `#pragma OPENCL EXTENSION cl_khr_fp64 : enable
+
+#define CL_LAYOUT_CHECK(name, cond) typedef char name[(cond) ? 1 : -1]
+
+typedef struct {
+	uchar A;
+	float3 B;
+} __attribute__((aligned(16))) testInner;
+CL_LAYOUT_CHECK(cl_layout_check_testInner_size, sizeof(testInner) == 32);
+CL_LAYOUT_CHECK(cl_layout_check_testInner_A, __builtin_offsetof(testInner, A) == 0);
+CL_LAYOUT_CHECK(cl_layout_check_testInner_B, __builtin_offsetof(testInner, B) == 16);
+
+typedef struct {
+	int Count;
+	double Scale;
+	ushort Flags[3];
+	testInner Inner;
+	int2 offset __attribute__((aligned(32)));
+} __attribute__((aligned(32))) testParams;
+CL_LAYOUT_CHECK(cl_layout_check_testParams_size, sizeof(testParams) == 96);
+CL_LAYOUT_CHECK(cl_layout_check_testParams_Count, __builtin_offsetof(testParams, Count) == 0);
+CL_LAYOUT_CHECK(cl_layout_check_testParams_Scale, __builtin_offsetof(testParams, Scale) == 8);
+CL_LAYOUT_CHECK(cl_layout_check_testParams_Flags, __builtin_offsetof(testParams, Flags) == 16);
+CL_LAYOUT_CHECK(cl_layout_check_testParams_Inner, __builtin_offsetof(testParams, Inner) == 32);
+CL_LAYOUT_CHECK(cl_layout_check_testParams_offset, __builtin_offsetof(testParams, offset) == 64);
+
+typedef struct __attribute__((packed)) {
+	uchar A;
+	uint B;
+	ushort C;
+} __attribute__((aligned(1))) testPacked;
+CL_LAYOUT_CHECK(cl_layout_check_testPacked_size, sizeof(testPacked) == 7);
+CL_LAYOUT_CHECK(cl_layout_check_testPacked_A, __builtin_offsetof(testPacked, A) == 0);
+CL_LAYOUT_CHECK(cl_layout_check_testPacked_B, __builtin_offsetof(testPacked, B) == 1);
+CL_LAYOUT_CHECK(cl_layout_check_testPacked_C, __builtin_offsetof(testPacked, C) == 5);
+`
+	if decls != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, decls)
+	}
+}
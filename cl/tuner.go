@@ -0,0 +1,270 @@
+package cl
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Tuner picks the fastest local work size for a kernel by timing the valid
+// candidates with profiling events. Results are cached per device, kernel
+// and global work size and, if Path is set, persisted as JSON so that the
+// tuning only happens once per machine.
+//
+// Kernels are identified by their function name, so use separate files for
+// programs that define different kernels with the same name.
+type Tuner struct {
+	// Path is the file the results are loaded from and saved to. If empty
+	// the results are only cached in memory.
+	Path string
+	// Iterations is the number of timed runs per candidate (after a
+	// warm-up run). The fastest run counts. Defaults to 3.
+	Iterations int
+
+	mu     sync.Mutex
+	loaded bool
+	cache  map[string][]int
+}
+
+// NewTuner returns a tuner persisting its results to path.
+func NewTuner(path string) *Tuner {
+	return &Tuner{Path: path}
+}
+
+// Tune returns the fastest local work size for running kernel on device
+// with globalWorkSize, timing candidates if the result isn't cached. If
+// setup is not nil it's called once before timing to set the kernel
+// arguments, otherwise the arguments already set are used. The kernel is
+// run several times per candidate so it must not depend on the output of
+// previous runs. A nil result means that letting the implementation
+// choose (a nil localWorkSize) was the fastest.
+func (t *Tuner) Tune(kernel *Kernel, device *Device, globalWorkSize []int, setup func(k *Kernel) error) ([]int, error) {
+	if len(globalWorkSize) < 1 || len(globalWorkSize) > 3 {
+		return nil, ErrInvalidWorkDimension
+	}
+	name, err := kernel.FunctionName()
+	if err != nil {
+		return nil, err
+	}
+	key := tunerKey(device, name, globalWorkSize)
+	t.mu.Lock()
+	if err := t.load(); err != nil {
+		t.mu.Unlock()
+		return nil, err
+	}
+	local, ok := t.cache[key]
+	t.mu.Unlock()
+	if ok {
+		return local, nil
+	}
+
+	candidates, err := kernel.localWorkSizeCandidates(device, globalWorkSize)
+	if err != nil {
+		return nil, err
+	}
+	if setup != nil {
+		if err := setup(kernel); err != nil {
+			return nil, err
+		}
+	}
+	context, err := kernel.Context()
+	if err != nil {
+		return nil, err
+	}
+	defer context.Release()
+	queue, err := context.CreateCommandQueue(device, CommandQueueProfilingEnable)
+	if err != nil {
+		return nil, err
+	}
+	defer queue.Release()
+
+	iterations := t.Iterations
+	if iterations <= 0 {
+		iterations = 3
+	}
+	var best int64 = -1
+	for _, c := range candidates {
+		d, err := timeKernel(queue, kernel, globalWorkSize, c, iterations)
+		if err == ErrInvalidWorkGroupSize || err == ErrInvalidWorkItemSize || err == ErrOutOfResources {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		if best < 0 || d < best {
+			best = d
+			local = c
+		}
+	}
+	if best < 0 {
+		return nil, fmt.Errorf("cl: no valid local work size for kernel %s with global work size %v", name, globalWorkSize)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.cache[key] = local
+	return local, t.save()
+}
+
+// timeKernel returns the shortest execution time in nanoseconds of
+// iterations runs of kernel after a warm-up run.
+func timeKernel(queue *CommandQueue, kernel *Kernel, globalWorkSize, localWorkSize []int, iterations int) (int64, error) {
+	var best int64 = -1
+	for i := -1; i < iterations; i++ {
+		event, err := queue.EnqueueNDRangeKernel(kernel, nil, globalWorkSize, localWorkSize, nil)
+		if err != nil {
+			return 0, err
+		}
+		if err := WaitForEvents([]*Event{event}); err != nil {
+			event.Release()
+			return 0, err
+		}
+		start, err := event.GetEventProfilingInfo(ProfilingInfoCommandStart)
+		if err != nil {
+			event.Release()
+			return 0, err
+		}
+		end, err := event.GetEventProfilingInfo(ProfilingInfoCommandEnd)
+		event.Release()
+		if err != nil {
+			return 0, err
+		}
+		if d := end - start; i >= 0 && (best < 0 || d < best) {
+			best = d
+		}
+	}
+	return best, nil
+}
+
+// localWorkSizeCandidates returns the local work sizes to try for the
+// kernel on device, starting with nil (implementation defined).
+func (k *Kernel) localWorkSizeCandidates(device *Device, globalWorkSize []int) ([][]int, error) {
+	required, err := k.CompileWorkGroupSize(device)
+	if err != nil {
+		return nil, err
+	}
+	if required != [3]int{} {
+		return [][]int{required[:len(globalWorkSize)]}, nil
+	}
+	maxSize, err := k.WorkGroupSize(device)
+	if err != nil {
+		return nil, err
+	}
+	if deviceMax := device.MaxWorkGroupSize(); deviceMax < maxSize {
+		maxSize = deviceMax
+	}
+	multiple, err := k.PreferredWorkGroupSizeMultiple(device)
+	if err != nil {
+		return nil, err
+	}
+	candidates := localWorkSizeCandidates(globalWorkSize, maxSize, device.MaxWorkItemSizes(), multiple)
+	return append([][]int{nil}, candidates...), nil
+}
+
+// localWorkSizeCandidates enumerates local work sizes that evenly divide
+// globalWorkSize and fit in maxSize work-items and maxItemSizes per
+// dimension. Each dimension is a power of two or a multiple of the
+// preferred multiple. If possible only work-group sizes that are a
+// multiple of the preferred multiple are returned.
+func localWorkSizeCandidates(globalWorkSize []int, maxSize int, maxItemSizes []int, multiple int) [][]int {
+	if len(globalWorkSize) > len(maxItemSizes) {
+		return nil
+	}
+	dims := make([][]int, len(globalWorkSize))
+	for d, global := range globalWorkSize {
+		limit := maxItemSizes[d]
+		if limit > maxSize {
+			limit = maxSize
+		}
+		for n := 1; n <= limit && n <= global; n++ {
+			if global%n == 0 && (n&(n-1) == 0 || (multiple > 0 && n%multiple == 0)) {
+				dims[d] = append(dims[d], n)
+			}
+		}
+	}
+	var all, preferred [][]int
+	var walk func(d, size int, local []int)
+	walk = func(d, size int, local []int) {
+		if d == len(dims) {
+			c := append([]int(nil), local...)
+			all = append(all, c)
+			if multiple > 0 && size%multiple == 0 {
+				preferred = append(preferred, c)
+			}
+			return
+		}
+		for _, n := range dims[d] {
+			if size*n > maxSize {
+				break
+			}
+			walk(d+1, size*n, append(local, n))
+		}
+	}
+	walk(0, 1, nil)
+	if len(preferred) > 0 {
+		return preferred
+	}
+	return all
+}
+
+func tunerKey(device *Device, kernelName string, globalWorkSize []int) string {
+	sizes := make([]string, len(globalWorkSize))
+	for i, s := range globalWorkSize {
+		sizes[i] = strconv.Itoa(s)
+	}
+	return strings.Join([]string{device.Vendor(), device.Name(), device.DriverVersion(), kernelName, strings.Join(sizes, "x")}, "|")
+}
+
+func (t *Tuner) load() error {
+	if t.loaded {
+		return nil
+	}
+	t.cache = make(map[string][]int)
+	if t.Path != "" {
+		data, err := ioutil.ReadFile(t.Path)
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		if len(data) > 0 {
+			if err := json.Unmarshal(data, &t.cache); err != nil {
+				return fmt.Errorf("cl: invalid tuner file %s: %s", t.Path, err)
+			}
+		}
+	}
+	t.loaded = true
+	return nil
+}
+
+// save writes the cache to t.Path, replacing the file atomically.
+func (t *Tuner) save() error {
+	if t.Path == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(t.cache, "", "\t")
+	if err != nil {
+		return err
+	}
+	f, err := ioutil.TempFile(filepath.Dir(t.Path), filepath.Base(t.Path)+".tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	// TempFile creates the file with mode 0600.
+	if err := os.Chmod(f.Name(), 0644); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	return os.Rename(f.Name(), t.Path)
+}
@@ -0,0 +1,50 @@
+package cl
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLocalWorkSizeCandidates(t *testing.T) {
+	cases := []struct {
+		global   []int
+		maxSize  int
+		maxItems []int
+		multiple int
+		expected [][]int
+	}{
+		{[]int{64}, 256, []int{256, 256, 256}, 32, [][]int{{32}, {64}}},
+		{[]int{96}, 256, []int{256, 256, 256}, 32, [][]int{{32}, {96}}},
+		// No candidate is a multiple of 32 so all valid sizes are returned
+		{[]int{12}, 256, []int{256, 256, 256}, 32, [][]int{{1}, {2}, {4}}},
+		{[]int{4, 4}, 8, []int{2, 8, 8}, 8, [][]int{{2, 4}}},
+		{[]int{4, 4, 4}, 64, []int{64}, 1, nil},
+	}
+	for _, c := range cases {
+		candidates := localWorkSizeCandidates(c.global, c.maxSize, c.maxItems, c.multiple)
+		if !reflect.DeepEqual(candidates, c.expected) {
+			t.Errorf("global %v: expected %v got %v", c.global, c.expected, candidates)
+		}
+	}
+}
+
+func TestTunerPersistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tuning.json")
+	tuner := NewTuner(path)
+	if err := tuner.load(); err != nil {
+		t.Fatal(err)
+	}
+	tuner.cache["dev|square|1024"] = []int{64}
+	tuner.cache["dev|square|7"] = nil
+	if err := tuner.save(); err != nil {
+		t.Fatal(err)
+	}
+	loaded := NewTuner(path)
+	if err := loaded.load(); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(loaded.cache, tuner.cache) {
+		t.Errorf("expected %v got %v", tuner.cache, loaded.cache)
+	}
+}
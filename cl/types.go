@@ -9,6 +9,7 @@ import (
 	"reflect"
 	"runtime"
 	"strings"
+	"sync"
 	"unsafe"
 )
 
@@ -365,8 +366,91 @@ const (
 	CommmandExecStatusQueued    CommmandExecStatus = C.CL_QUEUED
 )
 
+var commandExecStatusNameMap = map[CommmandExecStatus]string{
+	CommmandExecStatusComplete:  "Complete",
+	CommmandExecStatusRunning:   "Running",
+	CommmandExecStatusSubmitted: "Submitted",
+	CommmandExecStatusQueued:    "Queued",
+}
+
+// String returns the name of the status or, for a negative status of a
+// command that terminated abnormally, the error.
+func (s CommmandExecStatus) String() string {
+	if s < 0 {
+		return toError(C.cl_int(s)).Error()
+	}
+	name := commandExecStatusNameMap[s]
+	if name == "" {
+		name = fmt.Sprintf("Unknown(%x)", int(s))
+	}
+	return name
+}
+
+// CommandType is the type of the command an event belongs to.
+type CommandType int
+
+const (
+	CommandTypeNDRangeKernel     CommandType = C.CL_COMMAND_NDRANGE_KERNEL
+	CommandTypeTask              CommandType = C.CL_COMMAND_TASK
+	CommandTypeNativeKernel      CommandType = C.CL_COMMAND_NATIVE_KERNEL
+	CommandTypeReadBuffer        CommandType = C.CL_COMMAND_READ_BUFFER
+	CommandTypeWriteBuffer       CommandType = C.CL_COMMAND_WRITE_BUFFER
+	CommandTypeCopyBuffer        CommandType = C.CL_COMMAND_COPY_BUFFER
+	CommandTypeReadImage         CommandType = C.CL_COMMAND_READ_IMAGE
+	CommandTypeWriteImage        CommandType = C.CL_COMMAND_WRITE_IMAGE
+	CommandTypeCopyImage         CommandType = C.CL_COMMAND_COPY_IMAGE
+	CommandTypeCopyImageToBuffer CommandType = C.CL_COMMAND_COPY_IMAGE_TO_BUFFER
+	CommandTypeCopyBufferToImage CommandType = C.CL_COMMAND_COPY_BUFFER_TO_IMAGE
+	CommandTypeMapBuffer         CommandType = C.CL_COMMAND_MAP_BUFFER
+	CommandTypeMapImage          CommandType = C.CL_COMMAND_MAP_IMAGE
+	CommandTypeUnmapMemObject    CommandType = C.CL_COMMAND_UNMAP_MEM_OBJECT
+	CommandTypeMarker            CommandType = C.CL_COMMAND_MARKER
+	CommandTypeAcquireGLObjects  CommandType = C.CL_COMMAND_ACQUIRE_GL_OBJECTS
+	CommandTypeReleaseGLObjects  CommandType = C.CL_COMMAND_RELEASE_GL_OBJECTS
+	CommandTypeReadBufferRect    CommandType = C.CL_COMMAND_READ_BUFFER_RECT
+	CommandTypeWriteBufferRect   CommandType = C.CL_COMMAND_WRITE_BUFFER_RECT
+	CommandTypeCopyBufferRect    CommandType = C.CL_COMMAND_COPY_BUFFER_RECT
+	CommandTypeUser              CommandType = C.CL_COMMAND_USER
+)
+
+var commandTypeNameMap = map[CommandType]string{
+	CommandTypeNDRangeKernel:     "NDRangeKernel",
+	CommandTypeTask:              "Task",
+	CommandTypeNativeKernel:      "NativeKernel",
+	CommandTypeReadBuffer:        "ReadBuffer",
+	CommandTypeWriteBuffer:       "WriteBuffer",
+	CommandTypeCopyBuffer:        "CopyBuffer",
+	CommandTypeReadImage:         "ReadImage",
+	CommandTypeWriteImage:        "WriteImage",
+	CommandTypeCopyImage:         "CopyImage",
+	CommandTypeCopyImageToBuffer: "CopyImageToBuffer",
+	CommandTypeCopyBufferToImage: "CopyBufferToImage",
+	CommandTypeMapBuffer:         "MapBuffer",
+	CommandTypeMapImage:          "MapImage",
+	CommandTypeUnmapMemObject:    "UnmapMemObject",
+	CommandTypeMarker:            "Marker",
+	CommandTypeAcquireGLObjects:  "AcquireGLObjects",
+	CommandTypeReleaseGLObjects:  "ReleaseGLObjects",
+	CommandTypeReadBufferRect:    "ReadBufferRect",
+	CommandTypeWriteBufferRect:   "WriteBufferRect",
+	CommandTypeCopyBufferRect:    "CopyBufferRect",
+	CommandTypeUser:              "User",
+}
+
+func (ct CommandType) String() string {
+	name := commandTypeNameMap[ct]
+	if name == "" {
+		name = fmt.Sprintf("Unknown(%x)", int(ct))
+	}
+	return name
+}
+
 type Event struct {
 	clEvent C.cl_event
+
+	doneOnce sync.Once
+	done     chan struct{}
+	err      error
 }
 
 func releaseEvent(ev *Event) {
@@ -445,6 +529,38 @@ func sizeT3(i3 [3]int) [3]C.size_t {
 	return val
 }
 
+// slicePointer returns a pointer to the data of a slice and its size in
+// bytes. The elements must be numbers or (nested) arrays of them, such as
+// vectors, whose Go memory layout matches OpenCL C. Structs are rejected
+// since Go doesn't lay them out (size, alignment and padding) as OpenCL C
+// does, and bool since it isn't allowed in buffers. Arrays of 3 numbers are
+// rejected too as they are 12 bytes while the OpenCL C 3-component vectors
+// take the space of 4 (use Float3, etc.).
+func slicePointer(slice interface{}) (unsafe.Pointer, int, error) {
+	v := reflect.ValueOf(slice)
+	if v.Kind() != reflect.Slice || !isPlainData(v.Type().Elem()) {
+		return nil, 0, fmt.Errorf("cl: unsupported buffer data type %T", slice)
+	}
+	if v.Len() == 0 {
+		return nil, 0, ErrInvalidBufferSize
+	}
+	return unsafe.Pointer(v.Pointer()), v.Len() * int(v.Type().Elem().Size()), nil
+}
+
+func isPlainData(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Int8, reflect.Uint8, reflect.Int16, reflect.Uint16,
+		reflect.Int32, reflect.Uint32, reflect.Int64, reflect.Uint64, reflect.Float32, reflect.Float64:
+		return true
+	case reflect.Array:
+		if t.Len() == 3 && t.Elem().Kind() != reflect.Array {
+			return false
+		}
+		return isPlainData(t.Elem())
+	}
+	return false
+}
+
 type MappedMemObject struct {
 	ptr        unsafe.Pointer
 	size       int
@@ -453,11 +569,16 @@ type MappedMemObject struct {
 }
 
 func (mb *MappedMemObject) ByteSlice() []byte {
+	return hostBytes(mb.ptr, mb.size)
+}
+
+// hostBytes returns a byte slice backed by size bytes of C memory at ptr.
+func hostBytes(ptr unsafe.Pointer, size int) []byte {
 	var byteSlice []byte
 	sliceHeader := (*reflect.SliceHeader)(unsafe.Pointer(&byteSlice))
-	sliceHeader.Cap = mb.size
-	sliceHeader.Len = mb.size
-	sliceHeader.Data = uintptr(mb.ptr)
+	sliceHeader.Cap = size
+	sliceHeader.Len = size
+	sliceHeader.Data = uintptr(ptr)
 	return byteSlice
 }
 
@@ -3,6 +3,14 @@
 package cl
 
 // #include "cl.h"
+//
+// #ifndef CL_COMMAND_SVM_FREE
+// #define CL_COMMAND_SVM_FREE    0x1209
+// #define CL_COMMAND_SVM_MEMCPY  0x120A
+// #define CL_COMMAND_SVM_MEMFILL 0x120B
+// #define CL_COMMAND_SVM_MAP     0x120C
+// #define CL_COMMAND_SVM_UNMAP   0x120D
+// #endif
 import "C"
 
 const (
@@ -23,6 +31,16 @@ const (
 	// guarantee that the pointer returned by clEnqueueMapBuffer or clEnqueueMapImage contains the
 	// latest bits in the region being mapped which can be a significant performance enhancement.
 	MapFlagWriteInvalidateRegion MapFlag = C.CL_MAP_WRITE_INVALIDATE_REGION
+
+	CommandTypeBarrier           CommandType = C.CL_COMMAND_BARRIER
+	CommandTypeMigrateMemObjects CommandType = C.CL_COMMAND_MIGRATE_MEM_OBJECTS
+	CommandTypeFillBuffer        CommandType = C.CL_COMMAND_FILL_BUFFER
+	CommandTypeFillImage         CommandType = C.CL_COMMAND_FILL_IMAGE
+	CommandTypeSVMFree           CommandType = C.CL_COMMAND_SVM_FREE    // OpenCL 2.0
+	CommandTypeSVMMemcpy         CommandType = C.CL_COMMAND_SVM_MEMCPY  // OpenCL 2.0
+	CommandTypeSVMMemFill        CommandType = C.CL_COMMAND_SVM_MEMFILL // OpenCL 2.0
+	CommandTypeSVMMap            CommandType = C.CL_COMMAND_SVM_MAP     // OpenCL 2.0
+	CommandTypeSVMUnmap          CommandType = C.CL_COMMAND_SVM_UNMAP   // OpenCL 2.0
 )
 
 func init() {
@@ -38,6 +56,15 @@ func init() {
 	channelOrderNameMap[ChannelOrderDepth] = "Depth"
 	channelOrderNameMap[ChannelOrderDepthStencil] = "DepthStencil"
 	channelDataTypeNameMap[ChannelDataTypeUNormInt24] = "UNormInt24"
+	commandTypeNameMap[CommandTypeBarrier] = "Barrier"
+	commandTypeNameMap[CommandTypeMigrateMemObjects] = "MigrateMemObjects"
+	commandTypeNameMap[CommandTypeFillBuffer] = "FillBuffer"
+	commandTypeNameMap[CommandTypeFillImage] = "FillImage"
+	commandTypeNameMap[CommandTypeSVMFree] = "SVMFree"
+	commandTypeNameMap[CommandTypeSVMMemcpy] = "SVMMemcpy"
+	commandTypeNameMap[CommandTypeSVMMemFill] = "SVMMemFill"
+	commandTypeNameMap[CommandTypeSVMMap] = "SVMMap"
+	commandTypeNameMap[CommandTypeSVMUnmap] = "SVMUnmap"
 }
 
 type ImageDescription struct {
@@ -0,0 +1,46 @@
+package cl
+
+import "testing"
+
+func TestSlicePointer(t *testing.T) {
+	cases := []struct {
+		slice interface{}
+		size  int
+	}{
+		{[]float32{1, 2, 3}, 12},
+		{[]int8{1}, 1},
+		{[]Float4{{}, {}}, 32},
+		{[]Float3{{}}, 16},
+		{[][2]uint16{{}, {}}, 8},
+		{[][3]Float4{{}}, 48},
+	}
+	for _, c := range cases {
+		_, size, err := slicePointer(c.slice)
+		if err != nil {
+			t.Errorf("slicePointer(%T) failed: %s", c.slice, err)
+		} else if size != c.size {
+			t.Errorf("slicePointer(%T) returned size %d, expected %d", c.slice, size, c.size)
+		}
+	}
+
+	rejected := []interface{}{
+		[]struct {
+			A float32
+			B Float4
+		}{{}},
+		[][2]struct{ A int32 }{{}},
+		[]bool{true},
+		[][4]bool{{}},
+		[][3]float32{{}},
+		[][2][3]uint16{{}},
+		[]int{1},
+		[]*float32{nil},
+		[]string{""},
+		float32(1),
+	}
+	for _, slice := range rejected {
+		if _, _, err := slicePointer(slice); err == nil {
+			t.Errorf("slicePointer(%T) should fail", slice)
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package cl
+
+import "unsafe"
+
+//go:generate go run genvectors.go
+
+// Vector is implemented by the OpenCL vector types (Float4, Int2, UChar16,
+// etc.). The Go types follow the OpenCL size rules: a vector of n components
+// has the size of n elements except for 3-component vectors which have the
+// size of 4. OpenCL aligns vectors to their size.
+//
+// Vectors can be passed to Kernel.SetArg and used as elements of slices
+// passed to the buffer functions that take a slice (e.g. CreateBufferSlice).
+type Vector interface {
+	// Len returns the number of components of the vector.
+	Len() int
+	// Size returns the size in bytes of the vector, which is also its
+	// alignment in OpenCL.
+	Size() int
+	unsafePointer() unsafe.Pointer
+}
+
+// SetArgVector sets a vector argument (e.g. float4). It returns
+// ErrDoubleUnsupported for double vectors if the devices of the kernel
+// don't support double precision floating-point.
+func (k *Kernel) SetArgVector(index int, v Vector) error {
+	if _, ok := v.(interface{ double() }); ok && !k.supportsDouble() {
+		return ErrDoubleUnsupported
+	}
+	return k.SetArgUnsafe(index, v.Size(), v.unsafePointer())
+}